@@ -0,0 +1,50 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/eduardo-moro/metadata-editor/docx"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// anonymizeMetadata strips the properties that identify the author of an Office document
+func anonymizeMetadata(c *cli.Context) error {
+	filePath := c.String("file")
+
+	if err := validateFileExists(filePath); err != nil {
+		return err
+	}
+
+	doc, err := metadata.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+	d, ok := doc.(*docx.DOCX)
+	if !ok {
+		return fmt.Errorf("anonymize is only supported for DOCX, PPTX and XLSX files")
+	}
+
+	removed := d.Anonymize()
+
+	opts := saveOptionsFrom(c)
+	outputPath, err := saveDocument(doc, filePath, c.String("output"), opts)
+	if err != nil {
+		return err
+	}
+	if outputPath == stdoutPath || opts.Quiet {
+		// Keep the stream clean: the document is the only output, or --quiet wants none
+		return nil
+	}
+
+	if len(removed) == 0 {
+		fmt.Printf("✅ No identifying metadata found in %s\n", outputPath)
+		return nil
+	}
+
+	fmt.Printf("✅ Removed %d identifying value(s) from %s:\n", len(removed), outputPath)
+	for _, line := range removed {
+		fmt.Printf("   🗑️  %s\n", line)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// applyMetadata merges a JSON or YAML metadata file into a document
+func applyMetadata(c *cli.Context) error {
+	filePath := c.String("file")
+
+	if err := validateFileExists(filePath); err != nil {
+		return err
+	}
+
+	template, err := loadMetadataFile(c.String("from"))
+	if err != nil {
+		return err
+	}
+
+	doc, err := metadata.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+
+	dc := doc.GetMetadata()
+	dc.Merge(template, !c.Bool("append"))
+	if c.Bool("normalize") {
+		dc.Normalize()
+	}
+	if c.Bool("lowercase-keywords") {
+		dc.LowercaseKeywords()
+	}
+
+	opts := saveOptionsFrom(c)
+	outputPath, err := saveDocument(doc, filePath, c.String("output"), opts)
+	if err != nil {
+		return err
+	}
+	if outputPath == stdoutPath || opts.Quiet {
+		// Keep the stream clean: the document is the only output, or --quiet wants none
+		return nil
+	}
+
+	fmt.Printf("✅ Metadata applied successfully to %s\n", outputPath)
+	printMetadata(doc.GetMetadata())
+
+	return nil
+}
+
+// loadMetadataFile reads a Dublin Core object from a .json, .yaml or .yml file,
+// warning about keys that aren't known fields
+func loadMetadataFile(path string) (*dublincore.DublinCore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	return parseMetadata(data, ext == ".yaml" || ext == ".yml", path)
+}
+
+// readStdinMetadata reads a JSON Dublin Core object from stdin, warning about
+// keys that aren't known fields
+func readStdinMetadata() (*dublincore.DublinCore, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return parseMetadata(data, false, "stdin")
+}
+
+// parseMetadata decodes a Dublin Core object in JSON, or YAML when asYAML is
+// set, warning about unknown keys; source names the input in messages
+func parseMetadata(data []byte, asYAML bool, source string) (*dublincore.DublinCore, error) {
+	var raw map[string]any
+	if asYAML {
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		// Re-encode as JSON so both formats share the same decoding rules
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, fmt.Errorf("failed to convert YAML: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	var unknown []string
+	for key := range raw {
+		if !isFieldName(key) && key != "title_lang" && key != "description_lang" {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		fmt.Fprintf(os.Stderr, "⚠️  Ignoring unknown field %q in %s\n", key, source)
+	}
+
+	dc, err := dublincore.FromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata in %s: %w", source, err)
+	}
+	return dc, nil
+}
@@ -0,0 +1,278 @@
+package editor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// listFields are the fields whose flag values are split on commas
+var listFields = map[string]bool{
+	"creator":     true,
+	"subject":     true,
+	"contributor": true,
+	"keywords":    true,
+}
+
+// fieldAssignment is a parsed --set name=value pair
+type fieldAssignment struct {
+	Name   string
+	Values []string
+}
+
+// batchResult records the outcome for a single file
+type batchResult struct {
+	Path      string
+	Err       error
+	Unchanged bool // processed without changing the metadata
+}
+
+// batchMetadata applies the same --set assignments to every document in a directory
+func batchMetadata(c *cli.Context) error {
+	dir := c.String("dir")
+	dryRun := c.Bool("dry-run")
+
+	assignments, err := parseAssignments(c.StringSlice("set"))
+	if err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return fmt.Errorf("please provide at least one --set field=value")
+	}
+
+	files, err := findDocuments(dir)
+	if err != nil {
+		return err
+	}
+
+	concurrency := c.Int("concurrency")
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	opts := saveOptionsFrom(c)
+	results := runBatch(files, concurrency, func(path string, out io.Writer) error {
+		return applyAssignments(path, assignments, dryRun, opts, out)
+	})
+
+	return printBatchSummary(results, opts.Quiet)
+}
+
+// runBatch runs process on every file with at most concurrency files open at
+// once. Each file's output is buffered and printed whole as it finishes, a
+// progress counter is shown on stderr when it is a terminal, and results keep
+// the order of files.
+func runBatch(files []string, concurrency int, process func(path string, out io.Writer) error) []batchResult {
+	results := make([]batchResult, len(files))
+	progress := newProgress(len(files))
+
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, len(files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				var out bytes.Buffer
+				err := process(files[i], &out)
+				switch {
+				case errors.Is(err, errNoChanges):
+					results[i] = batchResult{Path: files[i], Unchanged: true}
+				case err != nil:
+					fmt.Fprintf(&out, "❌ %s: %v\n", files[i], err)
+					fallthrough
+				default:
+					results[i] = batchResult{Path: files[i], Err: err}
+				}
+
+				mu.Lock()
+				progress.print(out.Bytes())
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	progress.done()
+
+	return results
+}
+
+// batchProgress shows a "processed/total" counter on stderr when it is a terminal
+type batchProgress struct {
+	total, processed int
+	enabled          bool
+}
+
+// newProgress returns a counter for total files
+func newProgress(total int) *batchProgress {
+	return &batchProgress{
+		total:   total,
+		enabled: isTerminal(os.Stderr),
+	}
+}
+
+// print writes a finished file's output above the counter and advances it
+func (p *batchProgress) print(output []byte) {
+	p.processed++
+	if p.enabled {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+	os.Stdout.Write(output)
+	if p.enabled {
+		fmt.Fprintf(os.Stderr, "⏳ %d/%d", p.processed, p.total)
+	}
+}
+
+// done clears the counter
+func (p *batchProgress) done() {
+	if p.enabled {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
+
+// applyAssignments updates a single file, or only reports the changes in dry-run mode
+func applyAssignments(path string, assignments []fieldAssignment, dryRun bool, opts saveOptions, out io.Writer) error {
+	doc, err := metadata.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+	dc := doc.GetMetadata()
+	before := stateOf(doc)
+
+	if dryRun {
+		fmt.Fprintf(out, "📂 %s\n", path)
+	}
+	for _, a := range assignments {
+		if dryRun {
+			current, _ := dc.Get(a.Name)
+			fmt.Fprintf(out, "   %s: %s → %s\n", a.Name, getValueOrNone(current), getValueOrNone(a.Values))
+		}
+		if err := dc.Set(a.Name, a.Values); err != nil {
+			return err
+		}
+	}
+	if dryRun {
+		return nil
+	}
+
+	if unchanged(doc, before, opts) {
+		if !opts.Quiet {
+			fmt.Fprintf(out, "➖ %s: no changes\n", path)
+		}
+		return errNoChanges
+	}
+	if _, err := saveDocument(doc, path, "", opts); err != nil {
+		return err
+	}
+	if !opts.Quiet {
+		fmt.Fprintf(out, "✅ %s\n", path)
+	}
+	return nil
+}
+
+// parseAssignments parses name=value pairs, rejecting unknown field names
+func parseAssignments(pairs []string) ([]fieldAssignment, error) {
+	var assignments []fieldAssignment
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set value %q, expected field=value", pair)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isFieldName(name) {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+		assignments = append(assignments, fieldAssignment{Name: name, Values: parseFieldValue(name, value)})
+	}
+	return assignments, nil
+}
+
+// parseFieldValue splits list fields on commas and trims single-valued fields
+func parseFieldValue(name, value string) []string {
+	if listFields[name] {
+		return splitList(value)
+	}
+	if trimmed := strings.TrimSpace(value); trimmed != "" {
+		return []string{trimmed}
+	}
+	return nil
+}
+
+// findDocuments walks dir and returns every supported document in it, sorted by path
+func findDocuments(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && metadata.HasSupportedExtension(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %w", err)
+	}
+	return files, nil
+}
+
+// printBatchSummary prints the per-file outcome, unless quiet, and fails if
+// any file failed. It returns errNoChanges when no file was changed.
+func printBatchSummary(results []batchResult, quiet bool) error {
+	failed, same := 0, 0
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+		case r.Unchanged:
+			same++
+		}
+	}
+
+	if !quiet {
+		if same > 0 {
+			fmt.Printf("\n📊 Processed %d file(s): %d succeeded, %d unchanged, %d failed\n", len(results), len(results)-failed-same, same, failed)
+		} else {
+			fmt.Printf("\n📊 Processed %d file(s): %d succeeded, %d failed\n", len(results), len(results)-failed, failed)
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				fmt.Printf("   ❌ %s: %v\n", r.Path, r.Err)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d file(s) failed", failed, len(results))
+	}
+	if same > 0 && same == len(results) {
+		return errNoChanges
+	}
+	return nil
+}
+
+// isFieldName reports whether name is a field accepted by DublinCore.Set
+func isFieldName(name string) bool {
+	for _, field := range dublincore.FieldNames() {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// clearMetadata empties the fields named by --fields, or resets everything with --all
+func clearMetadata(c *cli.Context) error {
+	filePath := c.String("file")
+	fields := splitList(c.String("fields"))
+	all := c.Bool("all")
+
+	if !all && len(fields) == 0 {
+		return fmt.Errorf("please provide --fields or --all")
+	}
+	if all && len(fields) > 0 {
+		return fmt.Errorf("--fields and --all cannot be used together")
+	}
+
+	if err := validateFileExists(filePath); err != nil {
+		return err
+	}
+
+	doc, err := metadata.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+
+	if all {
+		// Keep only the structural defaults of a fresh document
+		dc := dublincore.New()
+		dc.Format = doc.GetMetadata().Format
+		doc.SetMetadata(dc)
+	}
+	for _, field := range fields {
+		if err := doc.GetMetadata().Set(field, nil); err != nil {
+			return err
+		}
+	}
+
+	opts := saveOptionsFrom(c)
+	outputPath, err := saveDocument(doc, filePath, c.String("output"), opts)
+	if err != nil {
+		return err
+	}
+	if outputPath == stdoutPath || opts.Quiet {
+		// Keep the stream clean: the document is the only output, or --quiet wants none
+		return nil
+	}
+
+	fmt.Printf("✅ Metadata cleared successfully in %s\n", outputPath)
+	printMetadata(doc.GetMetadata())
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+	"gopkg.in/yaml.v3"
+)
+
+// configNames are the files looked up, in the current directory and then in
+// the home directory, when --config isn't given
+var configNames = []string{".dceditor.yaml", ".dceditor.yml", ".dceditor.json"}
+
+// configFlag names the file with default flag values
+var configFlag = &cli.StringFlag{
+	Name:  "config",
+	Usage: "YAML or JSON file with default flag values (default: .dceditor.yaml in the current or home directory)",
+}
+
+// withConfig lets every flag of cmds take its default from the config file:
+// top-level keys apply to all commands, and a section named after a command
+// applies only to it. Flags given on the command line always win.
+func withConfig(cmds []*cli.Command) []*cli.Command {
+	for _, cmd := range cmds {
+		cmd.Flags = configurable(cmd.Flags)
+		cmd.Before = applyConfig(cmd.Name, cmd.Flags)
+		withConfig(cmd.Subcommands)
+	}
+	return cmds
+}
+
+// configurable wraps flags so altsrc can set them from the config file
+func configurable(flags []cli.Flag) []cli.Flag {
+	wrapped := make([]cli.Flag, len(flags))
+	for i, flag := range flags {
+		switch f := flag.(type) {
+		case *cli.BoolFlag:
+			wrapped[i] = altsrc.NewBoolFlag(f)
+		case *cli.StringFlag:
+			wrapped[i] = altsrc.NewStringFlag(f)
+		case *cli.StringSliceFlag:
+			wrapped[i] = altsrc.NewStringSliceFlag(f)
+		case *cli.IntFlag:
+			wrapped[i] = altsrc.NewIntFlag(f)
+		case *cli.Int64Flag:
+			wrapped[i] = altsrc.NewInt64Flag(f)
+		default:
+			wrapped[i] = flag
+		}
+	}
+	return wrapped
+}
+
+// applyConfig returns the Before hook that fills the unset flags of the
+// named command from the config file
+func applyConfig(command string, flags []cli.Flag) cli.BeforeFunc {
+	return func(c *cli.Context) error {
+		path, values, err := loadConfig(c.String("config"))
+		if err != nil || values == nil {
+			return err
+		}
+
+		source := altsrc.NewMapInputSource(path, configFor(values, command))
+		if err := altsrc.ApplyInputSourceValues(c, source, flags); err != nil {
+			return fmt.Errorf("invalid config %s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+// loadConfig reads the config file at path, or the first default one found
+// when path is empty. Without a config file it returns nil values.
+func loadConfig(path string) (string, map[string]any, error) {
+	if path == "" {
+		path = findConfig()
+		if path == "" {
+			return "", nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	// JSON is valid YAML, so one decoder reads both
+	var values map[string]any
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return "", nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if values == nil {
+		values = map[string]any{}
+	}
+	return path, values, nil
+}
+
+// findConfig returns the first of configNames found in the current or home
+// directory, or "" if there is none
+func findConfig() string {
+	dirs := []string{"."}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, home)
+	}
+
+	for _, dir := range dirs {
+		for _, name := range configNames {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// configFor returns the flag values that apply to command: the top-level
+// ones overridden by the command's own section. Other sections are dropped.
+func configFor(values map[string]any, command string) map[any]any {
+	flags := map[any]any{}
+	for key, value := range values {
+		if _, ok := value.(map[string]any); !ok {
+			flags[key] = value
+		}
+	}
+	if section, ok := values[command].(map[string]any); ok {
+		for key, value := range section {
+			flags[key] = value
+		}
+	}
+	return flags
+}
@@ -0,0 +1,81 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// copyMetadata copies the Dublin Core fields of one document into another
+func copyMetadata(c *cli.Context) error {
+	from, to := c.String("from"), c.String("to")
+
+	fields := splitList(c.String("fields"))
+	for i, field := range fields {
+		fields[i] = strings.ToLower(field)
+		if !isFieldName(fields[i]) {
+			return fmt.Errorf("unknown field: %s", field)
+		}
+	}
+
+	if err := validateFileExists(from); err != nil {
+		return err
+	}
+	if err := validateFileExists(to); err != nil {
+		return err
+	}
+
+	source, err := metadata.OpenReadOnly(from)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", from, err)
+	}
+	doc, err := metadata.Open(to)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", to, err)
+	}
+
+	copied := selectFields(source.GetMetadata(), fields)
+	doc.GetMetadata().Merge(copied, !c.Bool("append"))
+
+	opts := saveOptionsFrom(c)
+	outputPath, err := saveDocument(doc, to, c.String("output"), opts)
+	if err != nil {
+		return err
+	}
+	if outputPath == stdoutPath || opts.Quiet {
+		// Keep the stream clean: the document is the only output, or --quiet wants none
+		return nil
+	}
+
+	fmt.Printf("✅ Metadata copied from %s to %s\n", from, outputPath)
+	printMetadata(doc.GetMetadata())
+
+	return nil
+}
+
+// selectFields returns a copy of dc holding only the named fields. Without
+// names every field but format is kept, since format describes the source
+// file rather than its content.
+func selectFields(dc *dublincore.DublinCore, names []string) *dublincore.DublinCore {
+	if len(names) == 0 {
+		selected := dc.Clone()
+		selected.Format = nil
+		return selected
+	}
+
+	selected := &dublincore.DublinCore{}
+	for _, name := range names {
+		values, _ := dc.Get(name)
+		selected.Set(name, values)
+		switch name {
+		case "title":
+			selected.TitleLang = dc.TitleLang
+		case "description":
+			selected.DescriptionLang = dc.DescriptionLang
+		}
+	}
+	return selected
+}
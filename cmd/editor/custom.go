@@ -0,0 +1,73 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/docx"
+	"github.com/urfave/cli/v2"
+)
+
+// customProperties lists, sets or deletes the custom properties of a DOCX
+func customProperties(c *cli.Context) error {
+	filePath := c.String("file")
+
+	if err := validateFileExists(filePath); err != nil {
+		return err
+	}
+
+	doc, err := docx.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+
+	sets := c.StringSlice("set")
+	deletes := c.StringSlice("delete")
+	if len(sets) == 0 && len(deletes) == 0 {
+		printCustomProperties(doc)
+		return nil
+	}
+
+	for _, pair := range sets {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid --set value %q, expected name=value", pair)
+		}
+		if err := doc.SetCustomProperty(name, strings.TrimSpace(value)); err != nil {
+			return err
+		}
+	}
+	for _, name := range deletes {
+		if !doc.DeleteCustomProperty(strings.TrimSpace(name)) {
+			fmt.Printf("⚠️  Custom property %q not found\n", name)
+		}
+	}
+
+	opts := saveOptionsFrom(c)
+	outputPath, err := saveDocument(doc, filePath, c.String("output"), opts)
+	if err != nil {
+		return err
+	}
+	if outputPath == stdoutPath || opts.Quiet {
+		// Keep the stream clean: the document is the only output, or --quiet wants none
+		return nil
+	}
+
+	fmt.Printf("✅ Custom properties updated successfully in %s\n", outputPath)
+	printCustomProperties(doc)
+	return nil
+}
+
+// printCustomProperties prints one name = value line per custom property
+func printCustomProperties(doc *docx.DOCX) {
+	properties := doc.CustomProperties()
+	if len(properties) == 0 {
+		fmt.Println("No custom properties.")
+		return
+	}
+
+	fmt.Println("📊 Custom Properties:")
+	for _, property := range properties {
+		fmt.Printf("   %s = %s (%s)\n", property.Name, property.Value, property.Type)
+	}
+}
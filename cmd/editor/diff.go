@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// diffMetadata prints a field-by-field comparison of two documents
+func diffMetadata(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return fmt.Errorf("please provide two file paths to compare")
+	}
+	pathA, pathB := c.Args().Get(0), c.Args().Get(1)
+
+	a, err := openForDiff(pathA)
+	if err != nil {
+		return err
+	}
+	b, err := openForDiff(pathB)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📂 %s → %s\n", pathA, pathB)
+
+	diffs := dublincore.Diff(a.GetMetadata(), b.GetMetadata())
+	changed := make(map[string]dublincore.FieldDiff, len(diffs))
+	for _, d := range diffs {
+		changed[d.Field] = d
+	}
+
+	for _, name := range dublincore.FieldNames() {
+		d, ok := changed[name]
+		if !ok {
+			if c.Bool("verbose") {
+				values, _ := a.GetMetadata().Get(name)
+				fmt.Printf("  %-12s unchanged: %s\n", name, getValueOrNone(values))
+			}
+			continue
+		}
+		printFieldDiff(d)
+	}
+
+	if len(diffs) == 0 {
+		fmt.Println("✅ No metadata differences.")
+	}
+
+	return nil
+}
+
+// printFieldDiff prints the added and removed values of a changed field
+func printFieldDiff(d dublincore.FieldDiff) {
+	if len(d.Added) == 0 && len(d.Removed) == 0 {
+		fmt.Printf("~ %-12s reordered: %s → %s\n", d.Field, getValueOrNone(d.Before), getValueOrNone(d.After))
+		return
+	}
+
+	fmt.Printf("~ %s\n", d.Field)
+	for _, value := range d.Removed {
+		fmt.Printf("    - %s\n", value)
+	}
+	for _, value := range d.Added {
+		fmt.Printf("    + %s\n", value)
+	}
+}
+
+func openForDiff(filePath string) (metadata.Document, error) {
+	if err := validateFileExists(filePath); err != nil {
+		return nil, err
+	}
+	doc, err := metadata.OpenReadOnly(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	return doc, nil
+}
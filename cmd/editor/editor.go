@@ -2,22 +2,36 @@ package editor
 
 import (
 	"archive/zip"
+	"errors"
 	"fmt"
-	"io"
 	"os"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/eduardo-moro/metadata-editor/docx"
 	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/internal/fileutil"
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/eduardo-moro/metadata-editor/server"
 	"github.com/eduardo-moro/metadata-editor/ui"
 	"github.com/urfave/cli/v2"
 )
 
 func Main() {
+	appFlags := configurable(append(saveFlags(), configFlag))
+
 	app := &cli.App{
-		Name:  "dublin-core-editor",
-		Usage: "Edit Dublin Core metadata in DOCX files with a nice TUI",
-		Commands: []*cli.Command{
+		Name:    "dublin-core-editor",
+		Usage:   "Edit Dublin Core metadata in DOCX and ODT files with a nice TUI",
+		Version: metadata.Version,
+		// Keep commas inside --set values instead of splitting them into separate flags
+		DisableSliceFlagSeparator: true,
+		EnableBashCompletion:      true,
+		// Before every command, so only the top-level config keys apply here
+		Before: applyConfig("", appFlags),
+		Commands: withConfig([]*cli.Command{
 			{
 				Name:    "edit",
 				Aliases: []string{"e"},
@@ -28,14 +42,448 @@ func Main() {
 					}
 					filePath := c.Args().First()
 					outputPath := c.String("output")
-					return editWithTUI(filePath, outputPath)
+					vocabulary, err := readVocabulary(c.String("vocab"))
+					if err != nil {
+						return err
+					}
+					return editWithTUI(filePath, outputPath, vocabulary, saveOptionsFrom(c))
 				},
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.StringFlag{
 						Name:    "output",
 						Aliases: []string{"o"},
 						Usage:   "Output file (default: overwrite original)",
 					},
+					&cli.StringFlag{
+						Name:  "vocab",
+						Usage: "File with one keyword per line to suggest while typing keywords",
+					},
+					dryRunFlag,
+				}, saveFlags()...),
+			},
+			{
+				Name:         "set",
+				Usage:        "Set metadata fields without the TUI",
+				Action:       setMetadata,
+				BashComplete: completeSet,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Usage:    "DOCX file to edit",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file, or - for stdout (default: overwrite original)",
+					},
+					&cli.StringFlag{
+						Name:  "title",
+						Usage: "Document title",
+					},
+					&cli.BoolFlag{
+						Name:  "stdin-json",
+						Usage: "Merge a JSON metadata object read from stdin (same keys as apply) before applying the other flags",
+					},
+					&cli.BoolFlag{
+						Name:  "auto-title",
+						Usage: "When the title is empty, take it from the first Heading 1 (or first paragraph) of a DOCX",
+					},
+					&cli.BoolFlag{
+						Name:  "force-title",
+						Usage: "Like --auto-title, but replace a title that is already set",
+					},
+					&cli.StringFlag{
+						Name:  "creator",
+						Usage: "Creators (comma-separated)",
+					},
+					&cli.StringFlag{
+						Name:  "subject",
+						Usage: "Subjects (comma-separated)",
+					},
+					&cli.StringFlag{
+						Name:  "publisher",
+						Usage: "Publishers (comma-separated)",
+					},
+					&cli.StringFlag{
+						Name:  "contributor",
+						Usage: "Contributors (comma-separated)",
+					},
+					&cli.StringFlag{
+						Name:  "keywords",
+						Usage: "Keywords (comma-separated)",
+					},
+					&cli.StringFlag{
+						Name:  "description",
+						Usage: "Document description",
+					},
+					&cli.StringSliceFlag{
+						Name:  "identifier",
+						Usage: "Identifier as scheme:value, e.g. doi:10.1000/xyz or isbn:978-0-306-40615-7 (repeatable)",
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "Format, usually a MIME type (default: the type of the opened document)",
+					},
+					&cli.StringFlag{
+						Name:  "type",
+						Usage: "Resource type from the DCMI Type Vocabulary: " + strings.Join(dublincore.DCMITypes(), ", "),
+					},
+					&cli.BoolFlag{
+						Name:  "strict-type",
+						Usage: "Reject a --type outside the DCMI Type Vocabulary instead of warning",
+					},
+					&cli.StringSliceFlag{
+						Name:  "source",
+						Usage: "Work the document is derived from, e.g. a URL or identifier (repeatable)",
+					},
+					&cli.StringFlag{
+						Name:  "coverage",
+						Usage: "Spatial or temporal coverage (e.g. \"Brazil, 2020-2024\")",
+					},
+					&cli.StringFlag{
+						Name:  "license",
+						Usage: "Rights from a license id (e.g. CC-BY-4.0, MIT, all-rights-reserved)",
+					},
+					&cli.StringFlag{
+						Name:  "date",
+						Usage: "Publication date (YYYY-MM-DD or RFC3339)",
+					},
+					&cli.StringFlag{
+						Name:  "company",
+						Usage: "Company (docProps/app.xml)",
+					},
+					&cli.StringFlag{
+						Name:  "manager",
+						Usage: "Manager (docProps/app.xml)",
+					},
+					&cli.StringFlag{
+						Name:  "content-status",
+						Usage: "Content status, e.g. Draft or Final (cp:contentStatus)",
+					},
+					&cli.StringFlag{
+						Name:  "revision",
+						Usage: "Revision number (cp:revision)",
+					},
+					&cli.StringFlag{
+						Name:  "last-modified-by",
+						Usage: "Last author (cp:lastModifiedBy)",
+					},
+					&cli.StringFlag{
+						Name:  "doc-version",
+						Usage: "Document version (cp:version)",
+					},
+					dryRunFlag,
+				}, saveFlags()...),
+			},
+			{
+				Name:  "version",
+				Usage: "Print the editor version",
+				Action: func(c *cli.Context) error {
+					fmt.Printf("%s %s (%s, %s/%s)\n", c.App.Name, metadata.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+					return nil
+				},
+			},
+			{
+				Name:   "anonymize",
+				Usage:  "Remove author, company, revision and custom properties for privacy",
+				Action: anonymizeMetadata,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Usage:    "DOCX, PPTX or XLSX file to anonymize",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file, or - for stdout (default: overwrite original)",
+					},
+				}, saveFlags()...),
+			},
+			{
+				Name:   "clear",
+				Usage:  "Clear selected metadata fields",
+				Action: clearMetadata,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Usage:    "DOCX file to edit",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file, or - for stdout (default: overwrite original)",
+					},
+					&cli.StringFlag{
+						Name:  "fields",
+						Usage: "Fields to clear (comma-separated, e.g. creator,description)",
+					},
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Reset every field to its default",
+					},
+				}, saveFlags()...),
+			},
+			{
+				Name:   "apply",
+				Usage:  "Merge metadata from a JSON or YAML file into a document",
+				Action: applyMetadata,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Usage:    "Document to edit",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "from",
+						Usage:    "Metadata file (.json, .yaml or .yml)",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file, or - for stdout (default: overwrite original)",
+					},
+					&cli.BoolFlag{
+						Name:  "append",
+						Usage: "Append to existing values instead of replacing them",
+					},
+					&cli.BoolFlag{
+						Name:  "normalize",
+						Usage: "Trim list values and drop empty and case-insensitive duplicate entries",
+					},
+					&cli.BoolFlag{
+						Name:  "lowercase-keywords",
+						Usage: "Lowercase all keywords (implies --normalize)",
+					},
+				}, saveFlags()...),
+			},
+			{
+				Name:   "copy-meta",
+				Usage:  "Copy the metadata of one document into another",
+				Action: copyMetadata,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "from",
+						Usage:    "Document to copy the metadata from",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "Document to copy the metadata into",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file, or - for stdout (default: overwrite --to)",
+					},
+					&cli.StringFlag{
+						Name:  "fields",
+						Usage: "Fields to copy (comma-separated, e.g. creator,rights; default: all but format)",
+					},
+					&cli.BoolFlag{
+						Name:  "append",
+						Usage: "Append to existing values instead of replacing them",
+					},
+				}, saveFlags()...),
+			},
+			{
+				Name:   "serve",
+				Usage:  "Expose a REST API for reading and editing DOCX metadata",
+				Action: serveAPI,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "addr",
+						Usage: "Address to listen on",
+						Value: ":8080",
+					},
+					&cli.Int64Flag{
+						Name:  "max-size",
+						Usage: "Maximum request size in MB",
+						Value: server.DefaultMaxUploadSize >> 20,
+					},
+				},
+			},
+			{
+				Name:   "custom",
+				Usage:  "List, set or delete custom properties (docProps/custom.xml) of a DOCX",
+				Action: customProperties,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Usage:    "DOCX file",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file, or - for stdout (default: overwrite original)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "set",
+						Usage: "Text property as name=value (repeatable)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "delete",
+						Usage: "Name of a property to delete (repeatable)",
+					},
+				}, saveFlags()...),
+			},
+			{
+				Name:   "batch",
+				Usage:  "Apply the same metadata changes to every document in a directory",
+				Action: batchMetadata,
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:     "dir",
+						Usage:    "Directory to scan for documents",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "set",
+						Usage: "Field assignment as field=value (repeatable)",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Print what would change without saving",
+					},
+					&cli.IntFlag{
+						Name:  "concurrency",
+						Usage: "Number of files processed in parallel",
+						Value: runtime.NumCPU(),
+					},
+				}, saveFlags()...),
+			},
+			{
+				Name:   "export",
+				Usage:  "Export the metadata of a document or of every document in a directory",
+				Action: exportMetadata,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "file",
+						Usage: "Document to export",
+					},
+					&cli.StringFlag{
+						Name:  "dir",
+						Usage: "Directory to scan (including subdirectories)",
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "Report format (csv, json, xml, jsonld, html-meta); several comma-separated formats need --out-dir",
+						Value: "csv",
+					},
+					&cli.StringFlag{
+						Name:  "out",
+						Usage: "Output file (default: standard output)",
+					},
+					&cli.StringFlag{
+						Name:  "out-dir",
+						Usage: "Directory for one file per format, named after the document",
+					},
+					&cli.StringFlag{
+						Name:  "separator",
+						Usage: "Separator used to join multi-value fields",
+						Value: "; ",
+					},
+				},
+			},
+			{
+				Name:   "search",
+				Usage:  "List the documents in a directory whose fields contain a text",
+				Action: searchMetadata,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "dir",
+						Usage:    "Directory to scan (including subdirectories)",
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "field",
+						Usage: "Field to search, e.g. keywords (repeatable, paired with --contains)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "contains",
+						Usage: "Text the field must contain, ignoring case (repeatable; all pairs must match)",
+					},
+				},
+			},
+			{
+				Name:   "lint",
+				Usage:  "Check that documents have the required fields populated",
+				Action: lintMetadata,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "file",
+						Aliases: []string{"f"},
+						Usage:   "Document to check",
+					},
+					&cli.StringFlag{
+						Name:  "dir",
+						Usage: "Directory to check (including subdirectories)",
+					},
+					&cli.StringFlag{
+						Name:     "require",
+						Usage:    "Comma-separated required fields, e.g. title,creator,rights",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:   "stats",
+				Usage:  "Count how many documents in a directory have each field populated",
+				Action: statsMetadata,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "dir",
+						Usage:    "Directory to scan (including subdirectories)",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the summary as JSON",
+					},
+				},
+			},
+			{
+				Name:      "diff",
+				Usage:     "Compare metadata between two documents",
+				ArgsUsage: "<fileA.docx> <fileB.docx>",
+				Action:    diffMetadata,
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "verbose",
+						Usage: "Also list unchanged fields",
+					},
+				},
+			},
+			{
+				Name:   "restore",
+				Usage:  "Restore a file from its .backup copy",
+				Action: restoreBackup,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "file",
+						Aliases:  []string{"f"},
+						Usage:    "File to restore",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "keep",
+						Usage: "Keep the backup file after restoring",
+					},
+					&cli.StringFlag{
+						Name:  "backup-dir",
+						Usage: "Directory the backup was written to",
+					},
+					backupSuffixFlag(),
 				},
 			},
 			{
@@ -50,23 +498,47 @@ func Main() {
 						Usage:    "DOCX file to debug",
 						Required: true,
 					},
+					&cli.BoolFlag{
+						Name:  "list",
+						Usage: "List every zip part with its sizes and compression method",
+					},
 				},
 			},
 			{
-				Name:    "view",
-				Aliases: []string{"v"},
-				Usage:   "View current metadata",
-				Action:  viewMetadata,
+				Name:      "view",
+				Aliases:   []string{"v"},
+				Usage:     "View current metadata",
+				ArgsUsage: "[file | -]",
+				Action:    viewMetadata,
 				Flags: []cli.Flag{
 					&cli.StringFlag{
-						Name:     "file",
-						Aliases:  []string{"f"},
-						Usage:    "DOCX file to view",
-						Required: true,
+						Name:    "file",
+						Aliases: []string{"f"},
+						Usage:   "Document to view; - reads it (optionally gzipped) from stdin",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print all populated fields as JSON",
+					},
+					&cli.StringFlag{
+						Name:  "field",
+						Usage: "Print only this field, one value per line (fails if empty)",
+					},
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "Show every Dublin Core field, not only the main ones",
+					},
+					&cli.BoolFlag{
+						Name:  "no-emoji",
+						Usage: "Print plain labels without emoji",
+					},
+					&cli.BoolFlag{
+						Name:  "resolve-languages",
+						Usage: "Show language codes by name, e.g. en-US as English (United States)",
 					},
 				},
 			},
-		},
+		}),
 		// Default action if no command is specified
 		Action: func(c *cli.Context) error {
 			if c.NArg() == 0 {
@@ -74,132 +546,250 @@ func Main() {
 			}
 			// Default to edit command if file is provided without command
 			filePath := c.Args().First()
-			return editWithTUI(filePath, "")
+			return editWithTUI(filePath, "", nil, saveOptionsFrom(c))
 		},
+		Flags: appFlags,
 	}
 
 	if err := app.Run(os.Args); err != nil {
+		if errors.Is(err, errNoChanges) {
+			os.Exit(exitNoChanges)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 }
 
 // Add the viewMetadata function
 func viewMetadata(c *cli.Context) error {
 	filePath := c.String("file")
+	if filePath == "" {
+		filePath = c.Args().First()
+	}
+	if filePath == "" {
+		return fmt.Errorf("please provide a file, or - to read from stdin")
+	}
 
-	if err := validateFileExists(filePath); err != nil {
+	asJSON := c.Bool("json")
+
+	var doc metadata.Document
+	var err error
+	if filePath == stdinPath {
+		doc, err = openStdin()
+		filePath = "(stdin)"
+	} else {
+		if err := validateFileExists(filePath); err != nil {
+			return err
+		}
+		doc, err = metadata.OpenReadOnly(filePath)
+	}
+	if errors.Is(err, dublincore.ErrMalformedMetadata) && !asJSON {
+		// Don't show every field as "(none)" when the metadata is just unreadable
+		fmt.Printf("📂 File: %s\n", filePath)
 		return err
 	}
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+
+	warnDuplicateParts(doc, filePath)
+
+	if c.IsSet("field") {
+		return printField(doc.GetMetadata(), c.String("field"))
+	}
+
+	if asJSON {
+		data, err := doc.GetMetadata().ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to encode metadata: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
 
-	doc, err := docx.Open(filePath)
+	opts := dublincore.RenderOptions{
+		NoEmoji:          c.Bool("no-emoji"),
+		Placeholder:      "(none)",
+		ResolveLanguages: c.Bool("resolve-languages"),
+	}
+	if c.Bool("all") {
+		opts.Fields = dublincore.FieldNames()
+	}
+	out, err := dublincore.Render(doc.GetMetadata(), opts)
 	if err != nil {
-		return fmt.Errorf("failed to open DOCX file: %w", err)
+		return err
 	}
 
-	fmt.Printf("📂 File: %s\n", filePath)
+	if opts.NoEmoji {
+		fmt.Printf("File: %s\n", filePath)
+	} else {
+		fmt.Printf("📂 File: %s\n", filePath)
+	}
 	fmt.Println("Current metadata:")
-	printCurrentMetadata(doc.DublinCore)
+	fmt.Print(out)
+
+	if d, ok := doc.(*docx.DOCX); ok {
+		printOfficeProperties(d.Office, opts)
+	}
 
 	return nil
 }
 
-func editWithTUI(filePath, outputPath string) error {
-	// Open the DOCX file
-	doc, err := docx.Open(filePath)
+// officeLabels are the display labels of the Office core properties
+var officeLabels = []struct {
+	name, label, emoji string
+}{
+	{"contentStatus", "Content Status", "📌"},
+	{"revision", "Revision", "🔢"},
+	{"lastModifiedBy", "Last Modified By", "🧑"},
+	{"version", "Version", "🔖"},
+}
+
+// printOfficeProperties prints the populated Office core properties, or all of
+// them with the placeholder when opts lists every field, aligned like Render
+func printOfficeProperties(office docx.OfficeProperties, opts dublincore.RenderOptions) {
+	all := len(opts.Fields) > 0
+
+	width := 0
+	for _, entry := range officeLabels {
+		width = max(width, len(entry.label)+1)
+	}
+
+	for _, entry := range officeLabels {
+		value, _ := office.Get(entry.name)
+		if value == "" && !all {
+			continue
+		}
+		if value == "" {
+			value = opts.Placeholder
+		}
+
+		if !opts.NoEmoji {
+			fmt.Print(entry.emoji + " ")
+		}
+		fmt.Printf("%-*s %s\n", width, entry.label+":", value)
+	}
+}
+
+// readVocabulary reads the keyword suggestions of --vocab: one term per line,
+// skipping blank lines, # comments and repeated terms. An empty path reads nothing.
+func readVocabulary(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to open DOCX file: %w", err)
+		return nil, fmt.Errorf("failed to read vocabulary: %w", err)
 	}
 
-	fmt.Printf("📂 Opening: %s\n", filePath)
-	fmt.Println("Current metadata:")
-	printCurrentMetadata(doc.DublinCore)
-	fmt.Println("\nLoading TUI editor...")
-	fmt.Println("Note: Type your metadata and press Enter to submit.")
-	fmt.Println()
+	var terms []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(string(data), "\n") {
+		term := strings.TrimSpace(line)
+		if term == "" || strings.HasPrefix(term, "#") || seen[strings.ToLower(term)] {
+			continue
+		}
+		seen[strings.ToLower(term)] = true
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+func editWithTUI(filePath, outputPath string, vocabulary []string, opts saveOptions) error {
+	if outputPath == stdoutPath {
+		return fmt.Errorf("--output - is not supported by the TUI editor, use set or apply")
+	}
+	// Without a terminal BubbleTea can't read keys and would hang
+	if !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		return fmt.Errorf("the TUI editor needs an interactive terminal; in scripts and CI use set (e.g. set --file %q --title ...) or apply", filePath)
+	}
+
+	// Open the document
+	doc, err := metadata.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+	dc := doc.GetMetadata()
+
+	if !opts.Quiet {
+		fmt.Printf("📂 Opening: %s\n", filePath)
+		fmt.Println("Current metadata:")
+		printCurrentMetadata(dc)
+		fmt.Println("\nLoading TUI editor...")
+		fmt.Println("Note: Type your metadata and press Enter to submit.")
+		fmt.Println()
+	}
 
 	// Store original metadata for comparison
-	originalDC := &dublincore.DublinCore{}
-	originalDC.Title = append([]string{}, doc.DublinCore.Title...)
-	originalDC.Creator = append([]string{}, doc.DublinCore.Creator...)
-	originalDC.Keywords = append([]string{}, doc.DublinCore.Keywords...)
-	originalDC.Description = append([]string{}, doc.DublinCore.Description...)
-	originalDC.Category = append([]string{}, doc.DublinCore.Category...)
+	before := stateOf(doc)
 
 	// Run the BubbleTea TUI
-	updatedDC, cancelled, err := ui.RunEditor(doc.DublinCore)
+	updatedDC, cancelled, err := ui.RunEditor(dc, vocabulary)
 	if err != nil {
 		return fmt.Errorf("TUI editor failed: %w", err)
 	}
 
 	if cancelled {
-		fmt.Println("❌ Edit cancelled. No changes made.")
-		return nil
-	}
-
-	// Simple change detection - compare string representations
-	changesMade := hasChanges(originalDC, updatedDC)
-	if !changesMade {
-		fmt.Println("✅ No changes made. File remains unchanged.")
-		return nil
+		opts.status("❌ Edit cancelled. No changes made.\n")
+		return errNoChanges
 	}
 
 	// Update the document with new metadata
-	doc.DublinCore = updatedDC
+	doc.SetMetadata(updatedDC)
 
-	// Handle output path
-	if outputPath == "" {
-		backupPath := filePath + ".backup"
-		if err := createBackup(filePath, backupPath); err != nil {
-			return fmt.Errorf("backup failed: %w", err)
-		}
-		fmt.Printf("✅ Created backup: %s\n", backupPath)
-		outputPath = filePath
+	if unchanged(doc, before, opts) {
+		opts.status("✅ No changes made. File remains unchanged.\n")
+		return errNoChanges
 	}
 
-	// Save changes
-	if err := doc.Save(outputPath); err != nil {
-		return fmt.Errorf("failed to save DOCX file: %w", err)
+	if opts.DryRun {
+		return printDryRun(doc, opts)
 	}
 
-	fmt.Printf("\n✅ Metadata updated successfully in %s\n", outputPath)
-	fmt.Println("\nUpdated metadata:")
-	printMetadata(doc.DublinCore)
+	outputPath, err = saveDocument(doc, filePath, outputPath, opts)
+	if err != nil {
+		return err
+	}
+
+	if !opts.Quiet {
+		fmt.Printf("\n✅ Metadata updated successfully in %s\n", outputPath)
+		fmt.Println("\nUpdated metadata:")
+		printMetadata(updatedDC)
+	}
 
 	return nil
 }
 
-// Simple change detection function
-func hasChanges(original, updated *dublincore.DublinCore) bool {
-	if strings.Join(original.Title, "|") != strings.Join(updated.Title, "|") {
-		return true
-	}
-	if strings.Join(original.Creator, "|") != strings.Join(updated.Creator, "|") {
-		return true
+// printField prints each value of a single field on its own line, failing when it is empty
+func printField(dc *dublincore.DublinCore, name string) error {
+	values, err := dc.Get(name)
+	if err != nil {
+		return err
 	}
-	if strings.Join(original.Keywords, "|") != strings.Join(updated.Keywords, "|") {
-		return true
+
+	printed := 0
+	for _, value := range values {
+		if value != "" {
+			fmt.Println(value)
+			printed++
+		}
 	}
-	if strings.Join(original.Description, "|") != strings.Join(updated.Description, "|") {
-		return true
+	if printed == 0 {
+		return fmt.Errorf("field %s is empty", strings.ToLower(name))
 	}
-	return false
+	return nil
 }
 
+// printCurrentMetadata prints the main fields, marking empty ones as "(none)"
 func printCurrentMetadata(dc *dublincore.DublinCore) {
-	fmt.Printf("📝 Title:       %s\n", getValueOrNone(dc.Title))
-	fmt.Printf("👤 Creator(s):  %s\n", getValueOrNone(dc.Creator))
-	fmt.Printf("🔑 Keywords:    %s\n", getValueOrNone(dc.Keywords))
-	fmt.Printf("📋 Description: %s\n", getValueOrNone(dc.Description))
-	fmt.Printf("📂 Category:    %s\n", getValueOrNone(dc.Category))
+	out, _ := dublincore.Render(dc, dublincore.RenderOptions{Placeholder: "(none)"})
+	fmt.Print(out)
 }
 
+// printMetadata prints the main fields after an update
 func printMetadata(dc *dublincore.DublinCore) {
-	fmt.Printf("📝 Title:       %s\n", strings.Join(dc.Title, ", "))
-	fmt.Printf("👤 Creator(s):  %s\n", strings.Join(dc.Creator, ", "))
-	fmt.Printf("🔑 Keywords:    %s\n", strings.Join(dc.Keywords, ", "))
-	fmt.Printf("📋 Description: %s\n", strings.Join(dc.Description, ", "))
-	fmt.Printf("📂 Category:    %s\n", strings.Join(dc.Category, ", "))
+	out, _ := dublincore.Render(dc, dublincore.RenderOptions{})
+	fmt.Print(out)
 }
 
 func getValueOrNone(values []string) string {
@@ -209,29 +799,84 @@ func getValueOrNone(values []string) string {
 	return strings.Join(values, ", ")
 }
 
-func createBackup(src, dst string) error {
-	input, err := os.ReadFile(src)
+// saveDocument writes doc to outputPath, backing up filePath first when
+// overwriting the original. It returns the path that was written.
+func saveDocument(doc metadata.Document, filePath, outputPath string, opts saveOptions) (string, error) {
+	// Resolve paths once so backups and outputs don't depend on how they were spelled
+	filePath = absPath(filePath)
+	if outputPath != "" && outputPath != stdoutPath {
+		outputPath = absPath(outputPath)
+	}
+
+	original, err := os.Stat(filePath)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("failed to stat %s: %w", filePath, err)
 	}
-	return os.WriteFile(dst, input, 0644)
-}
+	warnDuplicateParts(doc, filePath)
 
-func hasRealChanges(original, updated *dublincore.DublinCore) bool {
-	// Simple comparison - if any field is different, changes were made
-	if strings.Join(original.Title, ",") != strings.Join(updated.Title, ",") {
-		return true
+	if err := prepareSave(doc, opts); err != nil {
+		return "", err
 	}
-	if strings.Join(original.Creator, ",") != strings.Join(updated.Creator, ",") {
-		return true
+
+	if outputPath == stdoutPath {
+		if err := doc.SaveTo(os.Stdout); err != nil {
+			return "", fmt.Errorf("failed to write document to stdout: %w", err)
+		}
+		return stdoutPath, nil
 	}
-	if strings.Join(original.Keywords, ",") != strings.Join(updated.Keywords, ",") {
-		return true
+
+	// Writing to the input through --output is an overwrite; ask first unless forced
+	if outputPath != "" && sameFile(filePath, outputPath) {
+		if !opts.Force && !confirm(fmt.Sprintf("⚠️  %s is also the input file. Overwrite it?", outputPath)) {
+			return "", fmt.Errorf("overwrite of %s cancelled", outputPath)
+		}
+		outputPath = ""
 	}
-	if strings.Join(original.Description, ",") != strings.Join(updated.Description, ",") {
-		return true
+
+	// Handle output path
+	inPlace := outputPath == ""
+	if inPlace {
+		if !opts.NoBackup && !opts.Force {
+			if opts.BackupDir != "" {
+				if err := os.MkdirAll(opts.BackupDir, 0755); err != nil {
+					return "", fmt.Errorf("backup failed: %w", err)
+				}
+			}
+			path := backupPath(filePath, opts.BackupDir, opts.backupSuffix(time.Now()))
+			if err := checkBackupTarget(filePath, path, opts.ReplaceBackup); err != nil {
+				return "", fmt.Errorf("backup failed: %w", err)
+			}
+			if err := createBackup(filePath, path); err != nil {
+				return "", fmt.Errorf("backup failed: %w", err)
+			}
+			opts.status("✅ Created backup: %s\n", path)
+		}
+		outputPath = filePath
 	}
-	return false
+
+	// Save changes
+	if err := doc.Save(outputPath); err != nil {
+		return "", fmt.Errorf("failed to save document: %w", err)
+	}
+
+	// A copy written elsewhere is a new file, so only an overwrite keeps the old times
+	if opts.PreserveTimes && inPlace {
+		if err := os.Chtimes(outputPath, fileutil.AccessTime(original), original.ModTime()); err != nil {
+			return "", fmt.Errorf("failed to restore file times: %w", err)
+		}
+	} else if opts.PreserveTimes {
+		fmt.Fprintf(os.Stderr, "⚠️  --preserve-times only applies when overwriting the original; %s gets the current time\n", outputPath)
+	}
+
+	return outputPath, nil
+}
+
+func createBackup(src, dst string) error {
+	input, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, input, 0644)
 }
 
 func debugDOCX(c *cli.Context) error {
@@ -260,23 +905,34 @@ func debugDOCX(c *cli.Context) error {
 		return fmt.Errorf("failed to create zip reader: %w", err)
 	}
 
-	// Look for core.xml
-	coreFile, err := findZipFile(reader, "docProps/core.xml")
+	if err := docx.ValidatePackage(reader); err != nil {
+		fmt.Printf("❌ Package structure: %v\n", err)
+	} else {
+		fmt.Println("✅ Package structure: [Content_Types].xml and main document part present")
+	}
+
+	if c.Bool("list") {
+		printParts(docx.ReadParts(reader))
+	}
+
+	// Look for core.xml where Open does, following _rels/.rels
+	corePath := docx.CorePartPath(reader)
+	coreFile, err := ziputil.FindFile(reader, corePath)
 	if err != nil {
 		return fmt.Errorf("core.xml not found: %w", err)
 	}
 
-	coreData, err := readZipFile(coreFile)
+	coreData, err := ziputil.ReadFile(coreFile)
 	if err != nil {
-		return fmt.Errorf("failed to read core.xml: %w", err)
+		return fmt.Errorf("failed to read %s: %w", coreFile.Name, err)
 	}
 
-	fmt.Println("=== Raw core.xml content ===")
+	fmt.Printf("=== Raw core.xml content (%s) ===\n", coreFile.Name)
 	fmt.Println(string(coreData))
 	fmt.Println("===========================")
 
 	// Try to parse it
-	doc, err := docx.Open(filePath)
+	doc, err := docx.OpenReadOnly(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open with docx parser: %w", err)
 	}
@@ -287,23 +943,19 @@ func debugDOCX(c *cli.Context) error {
 	return nil
 }
 
-func findZipFile(reader *zip.Reader, name string) (*zip.File, error) {
-	for _, file := range reader.File {
-		if file.Name == name {
-			return file, nil
-		}
+// printParts prints the zip entries of a package in archive order
+func printParts(parts []docx.PartInfo) {
+	width := len("Name")
+	for _, part := range parts {
+		width = max(width, len(part.Name))
 	}
-	return nil, fmt.Errorf("file not found: %s", name)
-}
 
-func readZipFile(file *zip.File) ([]byte, error) {
-	rc, err := file.Open()
-	if err != nil {
-		return nil, err
+	fmt.Printf("=== Zip parts (%d) ===\n", len(parts))
+	fmt.Printf("%-*s %12s %12s  %s\n", width, "Name", "Compressed", "Size", "Method")
+	for _, part := range parts {
+		fmt.Printf("%-*s %12d %12d  %s\n", width, part.Name, part.CompressedSize, part.UncompressedSize, part.Method)
 	}
-	defer rc.Close()
-
-	return io.ReadAll(rc)
+	fmt.Println("===========================")
 }
 
 func validateFileExists(filePath string) error {
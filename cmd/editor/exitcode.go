@@ -0,0 +1,57 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eduardo-moro/metadata-editor/docx"
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+)
+
+// Exit codes of set, edit and batch, so scripts can branch on the result
+const (
+	exitChanged   = 0 // metadata was written
+	exitError     = 1
+	exitNoChanges = 2 // the command ran but the metadata stayed the same
+)
+
+// errNoChanges is returned by commands that succeeded without changing any
+// metadata; Main turns it into exitNoChanges instead of reporting an error
+var errNoChanges = errors.New("no changes")
+
+// docState is the editable metadata of a document: Dublin Core plus, for
+// Office files, the core and app properties the CLI can set
+type docState struct {
+	dc               *dublincore.DublinCore
+	office           docx.OfficeProperties
+	company, manager string
+}
+
+// stateOf copies the current metadata of doc
+func stateOf(doc metadata.Document) docState {
+	state := docState{dc: doc.GetMetadata().Clone()}
+	if d, ok := doc.(*docx.DOCX); ok {
+		state.office = d.Office
+		if d.App != nil {
+			state.company, state.manager = d.App.Company, d.App.Manager
+		}
+	}
+	return state
+}
+
+// unchanged reports whether doc still has the metadata of before, counting
+// the keyword sync saveDocument applies
+func unchanged(doc metadata.Document, before docState, opts saveOptions) bool {
+	doc.GetMetadata().SyncKeywordsAndSubjects(opts.SyncKeywords)
+	after := stateOf(doc)
+	return after.dc.Equal(before.dc) && after.office == before.office &&
+		after.company == before.company && after.manager == before.manager
+}
+
+// status prints a progress message unless --quiet is set
+func (opts saveOptions) status(format string, a ...any) {
+	if !opts.Quiet {
+		fmt.Printf(format, a...)
+	}
+}
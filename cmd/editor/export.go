@@ -0,0 +1,229 @@
+package editor
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/export"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// exportMetadata writes the metadata of a document, or of every document in a
+// directory, as one or more reports
+func exportMetadata(c *cli.Context) error {
+	path, dir := c.String("file"), c.String("dir")
+	outPath, outDir := c.String("out"), c.String("out-dir")
+
+	switch {
+	case path == "" && dir == "":
+		return fmt.Errorf("either --file or --dir is required")
+	case path != "" && dir != "":
+		return fmt.Errorf("--file and --dir can't be used together")
+	case outPath != "" && outDir != "":
+		return fmt.Errorf("--out and --out-dir can't be used together")
+	case outDir != "" && path == "":
+		return fmt.Errorf("--out-dir names its files after the document, use --file")
+	}
+
+	// Check every format before writing anything
+	formats, err := parseExportFormats(c.String("format"))
+	if err != nil {
+		return err
+	}
+	for _, format := range formats {
+		if _, single := documentExporters[format]; single && dir != "" {
+			return fmt.Errorf("format %q exports a single document, use --file", format)
+		}
+	}
+	if len(formats) > 1 && outDir == "" {
+		return fmt.Errorf("exporting several formats at once needs --out-dir")
+	}
+
+	files := []string{path}
+	if dir != "" {
+		if files, err = findDocuments(dir); err != nil {
+			return err
+		}
+	}
+	separator := c.String("separator")
+
+	if outDir != "" {
+		return writeExportFiles(outDir, path, formats, separator)
+	}
+
+	var out io.Writer = os.Stdout
+	if outPath != "" && outPath != "-" {
+		file, err := os.Create(outPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	failed, err := writeExport(out, files, formats[0], separator)
+	if err != nil {
+		return err
+	}
+	if formats[0] == "csv" {
+		fmt.Fprintf(os.Stderr, "📊 Exported %d file(s)\n", len(files)-failed)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d file(s) could not be read", failed)
+	}
+	return nil
+}
+
+// exportExtensions maps every --format to the extension of the file --out-dir writes
+var exportExtensions = map[string]string{
+	"csv":       ".csv",
+	"json":      ".json",
+	"xml":       ".xml",
+	"jsonld":    ".jsonld",
+	"html-meta": ".html",
+}
+
+// exportFormatNames returns the supported --format values, sorted
+func exportFormatNames() []string {
+	names := make([]string, 0, len(exportExtensions))
+	for name := range exportExtensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseExportFormats splits a comma-separated --format value, rejecting unknown
+// names and dropping repeats
+func parseExportFormats(value string) ([]string, error) {
+	var formats []string
+	seen := map[string]bool{}
+	for _, format := range strings.Split(value, ",") {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "" || seen[format] {
+			continue
+		}
+		if _, ok := exportExtensions[format]; !ok {
+			return nil, fmt.Errorf("unsupported export format %q (supported: %s)", format, strings.Join(exportFormatNames(), ", "))
+		}
+		seen[format] = true
+		formats = append(formats, format)
+	}
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("please provide at least one --format")
+	}
+	return formats, nil
+}
+
+// writeExportFiles writes one report per format into outDir, named after the
+// document: cv.docx becomes cv.json, cv.csv...
+func writeExportFiles(outDir, path string, formats []string, separator string) error {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	for _, format := range formats {
+		outPath := filepath.Join(outDir, base+exportExtensions[format])
+		file, err := os.Create(outPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+
+		failed, err := writeExport(file, []string{path}, format, separator)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil && failed > 0 {
+			err = fmt.Errorf("%s could not be read", path)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Exported %s\n", outPath)
+	}
+	return nil
+}
+
+// writeExport writes files in format; only csv accepts several documents.
+// It returns how many files failed.
+func writeExport(out io.Writer, files []string, format, separator string) (int, error) {
+	if format == "csv" {
+		return writeCSV(out, files, separator)
+	}
+	return 0, writeDocumentExport(out, files[0], format)
+}
+
+// documentExporters render the metadata of a single document, keyed by --format
+var documentExporters = map[string]func(*dublincore.DublinCore) ([]byte, error){
+	"json":   (*dublincore.DublinCore).ToJSON,
+	"xml":    (*dublincore.DublinCore).ToXML,
+	"jsonld": export.JSONLD,
+	"html-meta": func(dc *dublincore.DublinCore) ([]byte, error) {
+		return export.HTMLMeta(dc), nil
+	},
+}
+
+// writeDocumentExport writes the metadata of a single document in format
+func writeDocumentExport(out io.Writer, path, format string) error {
+	doc, err := metadata.OpenReadOnly(path)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+
+	data, err := documentExporters[format](doc.GetMetadata())
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", format, err)
+	}
+
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	if _, err := out.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", format, err)
+	}
+	return nil
+}
+
+// writeCSV writes a header and one row per readable file, returning how many files failed
+func writeCSV(out io.Writer, files []string, separator string) (int, error) {
+	w := csv.NewWriter(out)
+	fields := dublincore.FieldNames()
+
+	if err := w.Write(append([]string{"file"}, fields...)); err != nil {
+		return 0, fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	failed := 0
+	for _, path := range files {
+		doc, err := metadata.OpenReadOnly(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", path, err)
+			failed++
+			continue
+		}
+
+		dc := doc.GetMetadata()
+		row := []string{path}
+		for _, field := range fields {
+			values, _ := dc.Get(field)
+			row = append(row, strings.Join(values, separator))
+		}
+		if err := w.Write(row); err != nil {
+			return failed, fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return failed, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return failed, nil
+}
@@ -0,0 +1,83 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// lintMetadata checks that documents have every required field populated
+func lintMetadata(c *cli.Context) error {
+	required, err := parseRequiredFields(c.String("require"))
+	if err != nil {
+		return err
+	}
+
+	filePath, dir := c.String("file"), c.String("dir")
+	switch {
+	case filePath != "" && dir != "":
+		return fmt.Errorf("use either --file or --dir, not both")
+	case filePath != "":
+		if err := validateFileExists(filePath); err != nil {
+			return err
+		}
+		if err := lintFile(filePath, required); err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+		fmt.Printf("✅ %s\n", filePath)
+		return nil
+	case dir != "":
+		files, err := findDocuments(dir)
+		if err != nil {
+			return err
+		}
+		var results []batchResult
+		for _, path := range files {
+			err := lintFile(path, required)
+			if err == nil {
+				fmt.Printf("✅ %s\n", path)
+			}
+			results = append(results, batchResult{Path: path, Err: err})
+		}
+		return printBatchSummary(results, false)
+	}
+	return fmt.Errorf("please provide --file or --dir")
+}
+
+// lintFile returns an error naming the required fields that are empty in path
+func lintFile(path string, required []string) error {
+	doc, err := metadata.OpenReadOnly(path)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+	dc := doc.GetMetadata()
+
+	var missing []string
+	for _, name := range required {
+		if values, _ := dc.Get(name); !hasValue(values) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required field(s): %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// parseRequiredFields parses the comma-separated --require list, rejecting unknown field names
+func parseRequiredFields(value string) ([]string, error) {
+	var fields []string
+	for _, name := range splitList(value) {
+		name = strings.ToLower(name)
+		if !isFieldName(name) {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+		fields = append(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("please provide at least one field with --require")
+	}
+	return fields, nil
+}
@@ -0,0 +1,34 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// restoreBackup puts the latest backup of a file back in place
+func restoreBackup(c *cli.Context) error {
+	filePath := c.String("file")
+	backupFile, err := latestBackup(filePath, c.String("backup-dir"), c.String("backup-suffix"))
+	if err != nil {
+		return err
+	}
+
+	// Make sure the backup is a readable document before replacing anything
+	if _, err := metadata.OpenReadOnly(backupFile); err != nil {
+		return fmt.Errorf("backup is not a readable document: %w", err)
+	}
+
+	if c.Bool("keep") {
+		if err := createBackup(backupFile, filePath); err != nil {
+			return fmt.Errorf("failed to restore backup: %w", err)
+		}
+	} else if err := os.Rename(backupFile, filePath); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	fmt.Printf("✅ Restored %s from %s\n", filePath, backupFile)
+	return nil
+}
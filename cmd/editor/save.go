@@ -0,0 +1,432 @@
+package editor
+
+import (
+	"archive/zip"
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eduardo-moro/metadata-editor/docx"
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/epub"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/eduardo-moro/metadata-editor/odt"
+	"github.com/urfave/cli/v2"
+)
+
+// saveOptions controls how a document is written back to disk
+type saveOptions struct {
+	NoBackup      bool
+	BackupDir     string // empty keeps the backup next to the original
+	BackupSuffix  string // appended to the file name of the backup
+	BackupStamp   bool   // insert the current time before the suffix to keep every backup
+	ReplaceBackup bool   // replace an existing backup target even if it isn't a backup of this file
+	PreserveTimes bool   // restore the original access and modification times after overwriting in place
+	Deterministic bool   // byte-identical output for the same input and metadata
+	WordLayout    bool   // write core.xml in Word's element order, line endings and BOM
+	DryRun        bool   // print the generated core.xml instead of saving
+	Force         bool   // overwrite without a backup or confirmation
+	Quiet         bool   // print only errors and requested output
+	StrictText    bool   // refuse values with invalid UTF-8 or control characters instead of cleaning them
+
+	// SyncKeywords mirrors keywords and subjects into each other before writing
+	SyncKeywords dublincore.SyncDirection
+
+	// Constraints are the limits a document must meet to be saved
+	Constraints dublincore.Constraints
+}
+
+// saveFlags are shared by every command that can overwrite a document
+func saveFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "Print only errors; the exit code tells the result (0 changed, 1 error, 2 no changes)",
+		},
+		&cli.BoolFlag{
+			Name:  "no-backup",
+			Usage: "Don't create a .backup copy before overwriting the original",
+		},
+		&cli.StringFlag{
+			Name:  "backup-dir",
+			Usage: "Directory for .backup copies (default: next to the original)",
+		},
+		backupSuffixFlag(),
+		&cli.BoolFlag{
+			Name:  "backup-timestamp",
+			Usage: "Name backups like cv.docx.2024-01-02T15-04-05.backup so earlier ones are kept",
+		},
+		&cli.BoolFlag{
+			Name:  "overwrite-backup",
+			Usage: "Replace an existing file at the backup path even if it isn't an earlier backup of this document",
+		},
+		&cli.BoolFlag{
+			Name:  "preserve-times",
+			Usage: "Keep the original access and modification times when overwriting the original file",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Overwrite without creating a backup or asking when --output is the input file",
+		},
+		&cli.BoolFlag{
+			Name:  "deterministic",
+			Usage: "Write reproducible output: original entry order, zeroed entry times",
+		},
+		&cli.BoolFlag{
+			Name:  "word-layout",
+			Usage: "Write core.xml as Word does (element order, CRLF, BOM) so re-saved Word files diff minimally",
+		},
+		&cli.StringFlag{
+			Name:  "sync-keywords",
+			Usage: "Copy terms between keywords and subject before saving: keywords-to-subjects, subjects-to-keywords or both",
+			Action: func(c *cli.Context, value string) error {
+				_, err := dublincore.ParseSyncDirection(value)
+				return err
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "strict-text",
+			Usage: "Refuse to save values with invalid UTF-8 or control characters instead of removing them",
+		},
+		&cli.IntFlag{
+			Name:  "max-title-len",
+			Usage: "Refuse to save when the title is longer than this many characters (0: no limit)",
+		},
+		&cli.IntFlag{
+			Name:  "max-keywords",
+			Usage: "Refuse to save when there are more keywords than this (0: no limit)",
+		},
+	}
+}
+
+// saveOptionsFrom reads the save flags of a command
+func saveOptionsFrom(c *cli.Context) saveOptions {
+	// Validated by the flag's Action
+	syncDirection, _ := dublincore.ParseSyncDirection(c.String("sync-keywords"))
+
+	return saveOptions{
+		NoBackup:      c.Bool("no-backup"),
+		BackupDir:     c.String("backup-dir"),
+		BackupSuffix:  c.String("backup-suffix"),
+		BackupStamp:   c.Bool("backup-timestamp"),
+		ReplaceBackup: c.Bool("overwrite-backup"),
+		PreserveTimes: c.Bool("preserve-times"),
+		Deterministic: c.Bool("deterministic"),
+		WordLayout:    c.Bool("word-layout"),
+		DryRun:        c.Bool("dry-run"),
+		Force:         c.Bool("force"),
+		Quiet:         quietFrom(c),
+		StrictText:    c.Bool("strict-text"),
+		SyncKeywords:  syncDirection,
+		Constraints: dublincore.Constraints{
+			MaxLength: map[string]int{"title": c.Int("max-title-len")},
+			MaxCount:  map[string]int{"keywords": c.Int("max-keywords")},
+		},
+	}
+}
+
+// defaultBackupSuffix is appended to backups unless --backup-suffix says otherwise
+const defaultBackupSuffix = ".backup"
+
+// backupStampLayout names timestamped backups; it sorts chronologically and
+// avoids the colons Windows doesn't allow in file names
+const backupStampLayout = "2006-01-02T15-04-05"
+
+// backupSuffixFlag is shared by the commands that write and restore backups
+func backupSuffixFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "backup-suffix",
+		Usage: "Suffix appended to the file name of backups",
+		Value: defaultBackupSuffix,
+		Action: func(c *cli.Context, value string) error {
+			if value == "" || strings.ContainsAny(value, `/\`) {
+				return fmt.Errorf("invalid --backup-suffix %q: must be non-empty and contain no path separators", value)
+			}
+			return nil
+		},
+	}
+}
+
+// warnDuplicateParts tells the user when an Office document repeats zip
+// entries, which saving will drop
+func warnDuplicateParts(doc metadata.Document, path string) {
+	d, ok := doc.(*docx.DOCX)
+	if !ok {
+		return
+	}
+	if duplicates := d.DuplicateParts(); len(duplicates) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  %s has duplicate zip entries (%s); only the first of each is kept when saving\n", path, strings.Join(duplicates, ", "))
+	}
+}
+
+// sanitizeText removes invalid UTF-8 and control characters from the metadata
+// before saving, warning about the fields it cleaned; with --strict-text it
+// refuses instead
+func sanitizeText(doc metadata.Document, opts saveOptions) error {
+	dc := doc.GetMetadata()
+	if opts.StrictText {
+		if errs := dc.CheckText(); len(errs) > 0 {
+			return fmt.Errorf("metadata can't be written as XML:\n%w", errors.Join(errs...))
+		}
+		return nil
+	}
+	if fields := dc.Sanitize(); len(fields) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  Removed invalid UTF-8 or control characters from: %s\n", strings.Join(fields, ", "))
+	}
+	return nil
+}
+
+// quietFrom reports whether --quiet was given to the command or before it:
+// the command's own unset flag would otherwise hide the app-level one
+func quietFrom(c *cli.Context) bool {
+	for _, ctx := range c.Lineage() {
+		if ctx.Bool("quiet") {
+			return true
+		}
+	}
+	return false
+}
+
+// backupPath returns the absolute path where the backup of filePath is stored
+func backupPath(filePath, dir, suffix string) string {
+	if suffix == "" {
+		suffix = defaultBackupSuffix
+	}
+	if dir == "" {
+		return absPath(filePath) + suffix
+	}
+	return filepath.Join(absPath(dir), filepath.Base(filePath)+suffix)
+}
+
+// backupSuffix returns the suffix of a new backup, timestamped with now if requested
+func (opts saveOptions) backupSuffix(now time.Time) string {
+	suffix := opts.BackupSuffix
+	if suffix == "" {
+		suffix = defaultBackupSuffix
+	}
+	if opts.BackupStamp {
+		return "." + now.Format(backupStampLayout) + suffix
+	}
+	return suffix
+}
+
+// latestBackup returns the most recent backup of filePath: the plain one if
+// it exists, otherwise the newest timestamped one
+func latestBackup(filePath, dir, suffix string) (string, error) {
+	plain := backupPath(filePath, dir, suffix)
+	if _, err := os.Stat(plain); err == nil {
+		return plain, nil
+	}
+
+	// Read the directory instead of globbing so names with [ or * still match
+	dir, base := filepath.Split(strings.TrimSuffix(plain, suffix))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("no backup found for %s (expected %s)", filePath, plain)
+	}
+	var matches []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, base+".") && strings.HasSuffix(name, suffix) &&
+			len(name) > len(base)+len(suffix) {
+			matches = append(matches, filepath.Join(dir, name))
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no backup found for %s (expected %s)", filePath, plain)
+	}
+
+	// Timestamps sort lexically, so the last match is the newest
+	sort.Strings(matches)
+	return matches[len(matches)-1], nil
+}
+
+// absPath resolves path against the working directory, keeping it cleaned
+// but relative if the working directory can't be determined
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// checkBackupTarget refuses backup paths that would clobber something other
+// than an earlier backup: a directory, a symlink, or the file being backed up.
+// Unless overwrite is set, an existing file must also be named like a backup
+// and hold a copy of filePath that differs only in its metadata.
+func checkBackupTarget(filePath, backup string, overwrite bool) error {
+	info, err := os.Lstat(backup)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s exists and is not a regular file", backup)
+	}
+	if sameFile(filePath, backup) {
+		return fmt.Errorf("%s is the file being backed up", backup)
+	}
+	if overwrite {
+		return nil
+	}
+	if metadata.HasSupportedExtension(backup) {
+		return fmt.Errorf("%s exists and is named like a document, not a backup (use --overwrite-backup to replace it)", backup)
+	}
+	if !isBackupOf(filePath, backup) {
+		return fmt.Errorf("%s exists and is not a backup of %s (use --overwrite-backup to replace it)", backup, filepath.Base(filePath))
+	}
+	return nil
+}
+
+// metadataParts are the entries saving may rewrite or add, besides the core
+// properties part and EPUB package documents; a backup may differ from the
+// file it was taken from only in these
+var metadataParts = []string{
+	"[Content_Types].xml", "_rels/.rels", "docProps/app.xml", "docProps/custom.xml", "meta.xml",
+}
+
+// isBackupOf reports whether backup holds an earlier version of filePath as
+// this tool writes it: the same entries with the same content, apart from
+// the metadata parts
+func isBackupOf(filePath, backup string) bool {
+	current, err := zip.OpenReader(filePath)
+	if err != nil {
+		return false
+	}
+	defer current.Close()
+	earlier, err := zip.OpenReader(backup)
+	if err != nil {
+		return false
+	}
+	defer earlier.Close()
+
+	a, b := contentEntries(&current.Reader), contentEntries(&earlier.Reader)
+	if len(a) != len(b) {
+		return false
+	}
+	for name, entry := range a {
+		if b[name] != entry {
+			return false
+		}
+	}
+	return true
+}
+
+// contentEntry identifies the content of a zip entry without decompressing it
+type contentEntry struct {
+	crc  uint32
+	size uint64
+}
+
+// contentEntries returns the entries of reader that aren't metadata parts,
+// keyed by lowercased name
+func contentEntries(reader *zip.Reader) map[string]contentEntry {
+	core := strings.ToLower(docx.CorePartPath(reader))
+	entries := map[string]contentEntry{}
+	for _, file := range reader.File {
+		name := strings.ToLower(file.Name)
+		if name == core || strings.HasSuffix(name, ".opf") || slices.ContainsFunc(metadataParts, func(part string) bool {
+			return strings.EqualFold(part, name)
+		}) {
+			continue
+		}
+		entries[name] = contentEntry{file.CRC32, file.UncompressedSize64}
+	}
+	return entries
+}
+
+// sameFile reports whether both paths name the same file; a missing output never does
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(infoA, infoB)
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "s", "sim":
+		return true
+	}
+	return false
+}
+
+// applyDeterministic turns on reproducible output for the formats that support it
+func applyDeterministic(doc metadata.Document) {
+	switch d := doc.(type) {
+	case *docx.DOCX:
+		d.Deterministic = true
+	case *odt.ODT:
+		d.Deterministic = true
+	case *epub.EPUB:
+		d.Deterministic = true
+	}
+}
+
+// applyWordLayout makes Office documents write core.xml the way Word does
+func applyWordLayout(doc metadata.Document) {
+	if d, ok := doc.(*docx.DOCX); ok {
+		layout := docx.WordMarshalOptions
+		d.Marshal = &layout
+	}
+}
+
+// dryRunFlag makes a writing command print the generated core.xml instead of saving
+var dryRunFlag = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "Print the core.xml that would be written without modifying any file",
+}
+
+// prepareSave runs the steps every save applies to doc before writing it:
+// keyword syncing, the configured limits, text sanitizing and the output layout
+func prepareSave(doc metadata.Document, opts saveOptions) error {
+	doc.GetMetadata().SyncKeywordsAndSubjects(opts.SyncKeywords)
+	if errs := doc.GetMetadata().ValidateConstraints(opts.Constraints); len(errs) > 0 {
+		return fmt.Errorf("metadata exceeds the configured limits:\n%w", errors.Join(errs...))
+	}
+	if err := sanitizeText(doc, opts); err != nil {
+		return err
+	}
+	if opts.Deterministic {
+		applyDeterministic(doc)
+	}
+	if opts.WordLayout {
+		applyWordLayout(doc)
+	}
+	return nil
+}
+
+// printDryRun prints the core.xml that saving doc with opts would write
+func printDryRun(doc metadata.Document, opts saveOptions) error {
+	d, ok := doc.(*docx.DOCX)
+	if !ok {
+		return fmt.Errorf("--dry-run prints docProps/core.xml and is only supported for DOCX files")
+	}
+	if err := prepareSave(d, opts); err != nil {
+		return err
+	}
+
+	data, err := d.CoreXML()
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,300 @@
+package editor
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/internal/fileutil"
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+)
+
+// minimalDOCX holds the parts of the smallest package docx.Open accepts
+var minimalDOCX = map[string]string{
+	"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/></Types>`,
+	"_rels/.rels":         `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/></Relationships>`,
+	"word/document.xml":   `<?xml version="1.0" encoding="UTF-8"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body/></w:document>`,
+	"docProps/core.xml":   `<?xml version="1.0" encoding="UTF-8"?><cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Analista</dc:title></cp:coreProperties>`,
+}
+
+// writeDOCX writes minimalDOCX to dir/name and returns its path
+func writeDOCX(t *testing.T, dir, name string) string {
+	t.Helper()
+	return writeDOCXWith(t, dir, name, nil)
+}
+
+// writeDOCXWith writes minimalDOCX to dir/name with some parts replaced
+func writeDOCXWith(t *testing.T, dir, name string, overrides map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	zipWriter := zip.NewWriter(file)
+	for _, part := range []string{"[Content_Types].xml", "_rels/.rels", "word/document.xml", "docProps/core.xml"} {
+		w, err := zipWriter.Create(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, ok := overrides[part]
+		if !ok {
+			content = minimalDOCX[part]
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// openAndRetitle opens path and changes its title so saving rewrites it
+func openAndRetitle(t *testing.T, path string) metadata.Document {
+	t.Helper()
+	return openWithTitle(t, path, "Analista Go")
+}
+
+// openWithTitle opens path and sets its title to title
+func openWithTitle(t *testing.T, path, title string) metadata.Document {
+	t.Helper()
+
+	doc, err := metadata.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	doc.GetMetadata().SetTitle(title)
+	return doc
+}
+
+func TestSaveDocumentPreserveTimes(t *testing.T) {
+	atime := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+	mtime := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("in place", func(t *testing.T) {
+		path := writeDOCX(t, t.TempDir(), "cv.docx")
+		doc := openAndRetitle(t, path)
+		// Set after opening, since reading the file may update its access time
+		if err := os.Chtimes(path, atime, mtime); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := saveDocument(doc, path, "", saveOptions{PreserveTimes: true, NoBackup: true, Quiet: true}); err != nil {
+			t.Fatalf("saveDocument: %v", err)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !info.ModTime().Equal(mtime) {
+			t.Errorf("modification time = %v, want %v", info.ModTime(), mtime)
+		}
+		if got := fileutil.AccessTime(info); !got.Equal(atime) && !got.Equal(mtime) {
+			// Platforms without access times report the modification time
+			t.Errorf("access time = %v, want %v", got, atime)
+		}
+	})
+
+	t.Run("other output", func(t *testing.T) {
+		dir := t.TempDir()
+		path := writeDOCX(t, dir, "cv.docx")
+		if err := os.Chtimes(path, atime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		output := filepath.Join(dir, "copy.docx")
+
+		doc := openAndRetitle(t, path)
+		if _, err := saveDocument(doc, path, output, saveOptions{PreserveTimes: true, Quiet: true}); err != nil {
+			t.Fatalf("saveDocument: %v", err)
+		}
+
+		info, err := os.Stat(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.ModTime().Equal(mtime) {
+			t.Errorf("copy got the original's modification time %v", mtime)
+		}
+	})
+}
+
+func TestCheckBackupTarget(t *testing.T) {
+	dir := t.TempDir()
+	path := writeDOCX(t, dir, "cv.docx")
+	retitled := strings.Replace(minimalDOCX["docProps/core.xml"], "Analista", "Analista Go", 1)
+	otherBody := strings.Replace(minimalDOCX["word/document.xml"], "<w:body/>", "<w:body><w:p/></w:body>", 1)
+
+	plain := filepath.Join(dir, "notes.backup")
+	if err := os.WriteFile(plain, []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		backup string
+		wantOK bool
+	}{
+		{"missing", filepath.Join(dir, "missing.backup"), true},
+		{"earlier backup", writeDOCXWith(t, dir, "cv.docx.backup", map[string]string{"docProps/core.xml": retitled}), true},
+		{"same content", writeDOCX(t, dir, "copy.backup"), true},
+		{"unrelated file", plain, false},
+		{"other document", writeDOCXWith(t, dir, "other.backup", map[string]string{"word/document.xml": otherBody}), false},
+		{"named like a document", writeDOCX(t, dir, "cv.backup.docx"), false},
+		{"the file itself", path, false},
+		{"directory", t.TempDir(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBackupTarget(path, tt.backup, false)
+			if (err == nil) != tt.wantOK {
+				t.Errorf("checkBackupTarget = %v, want ok = %v", err, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("overwrite", func(t *testing.T) {
+		for _, backup := range []string{plain, filepath.Join(dir, "other.backup"), filepath.Join(dir, "cv.backup.docx")} {
+			if err := checkBackupTarget(path, backup, true); err != nil {
+				t.Errorf("checkBackupTarget(%s) with overwrite = %v, want ok", filepath.Base(backup), err)
+			}
+		}
+		// Overwriting never reaches the file itself or a directory
+		for _, backup := range []string{path, dir} {
+			if err := checkBackupTarget(path, backup, true); err == nil {
+				t.Errorf("checkBackupTarget(%s) with overwrite succeeded, want an error", backup)
+			}
+		}
+	})
+}
+
+func TestSanitizeText(t *testing.T) {
+	path := writeDOCX(t, t.TempDir(), "cv.docx")
+
+	t.Run("strict", func(t *testing.T) {
+		doc := openWithTitle(t, path, "Analista\x00 Go\xff")
+		if err := sanitizeText(doc, saveOptions{StrictText: true}); err == nil {
+			t.Fatal("sanitizeText succeeded with --strict-text, want an error")
+		}
+		if got := doc.GetMetadata().Title[0]; got != "Analista\x00 Go\xff" {
+			t.Errorf("Title = %q after refusing, want it untouched", got)
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		doc := openWithTitle(t, path, "Analista\x00 Go\xff")
+		if err := sanitizeText(doc, saveOptions{}); err != nil {
+			t.Fatalf("sanitizeText: %v", err)
+		}
+		if got := doc.GetMetadata().Title[0]; got != "Analista Go\ufffd" {
+			t.Errorf("Title = %q, want the control character removed and the invalid byte replaced", got)
+		}
+	})
+}
+
+// captureStdout returns what fn prints to standard output
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	fnErr := fn()
+	os.Stdout = stdout
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), fnErr
+}
+
+func TestDryRunMatchesSave(t *testing.T) {
+	tests := []struct {
+		name string
+		opts saveOptions
+	}{
+		{"default", saveOptions{}},
+		{"word layout", saveOptions{WordLayout: true}},
+		{"deterministic", saveOptions{Deterministic: true}},
+		{"synced keywords", saveOptions{SyncKeywords: dublincore.SyncBoth}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := writeDOCX(t, dir, "cv.docx")
+			edit := func() metadata.Document {
+				doc := openWithTitle(t, path, "Analista\x00 Go")
+				doc.GetMetadata().Keywords = []string{"Go", "AWS\x1b"}
+				return doc
+			}
+
+			printed, err := captureStdout(t, func() error { return printDryRun(edit(), tt.opts) })
+			if err != nil {
+				t.Fatalf("printDryRun: %v", err)
+			}
+
+			output := filepath.Join(dir, "saved.docx")
+			opts := tt.opts
+			opts.Quiet = true
+			if _, err := saveDocument(edit(), path, output, opts); err != nil {
+				t.Fatalf("saveDocument: %v", err)
+			}
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			file, err := ziputil.FindFile(reader, "docProps/core.xml")
+			if err != nil {
+				t.Fatal(err)
+			}
+			saved, err := ziputil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := strings.TrimSuffix(printed, "\n"); got != string(saved) {
+				t.Errorf("dry run printed\n%s\nbut the save wrote\n%s", got, saved)
+			}
+		})
+	}
+
+	t.Run("limits", func(t *testing.T) {
+		path := writeDOCX(t, t.TempDir(), "cv.docx")
+		opts := saveOptions{Constraints: dublincore.Constraints{MaxLength: map[string]int{"title": 5}}}
+		printed, err := captureStdout(t, func() error { return printDryRun(openAndRetitle(t, path), opts) })
+		if err == nil || printed != "" {
+			t.Errorf("printDryRun = %q, %v with a title over the limit, want an error and no output", printed, err)
+		}
+	})
+
+	t.Run("strict text", func(t *testing.T) {
+		path := writeDOCX(t, t.TempDir(), "cv.docx")
+		printed, err := captureStdout(t, func() error {
+			return printDryRun(openWithTitle(t, path, "Analista\x00"), saveOptions{StrictText: true})
+		})
+		if err == nil || printed != "" {
+			t.Errorf("printDryRun = %q, %v with a control character and --strict-text, want an error and no output", printed, err)
+		}
+	})
+}
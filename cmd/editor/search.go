@@ -0,0 +1,85 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// searchTerm is a --field/--contains pair; every term must match
+type searchTerm struct {
+	field    string
+	contains string
+}
+
+// searchMetadata lists the documents in a directory whose fields contain the given substrings
+func searchMetadata(c *cli.Context) error {
+	terms, err := searchTerms(c.StringSlice("field"), c.StringSlice("contains"))
+	if err != nil {
+		return err
+	}
+
+	files, err := findDocuments(c.String("dir"))
+	if err != nil {
+		return err
+	}
+
+	matched := 0
+	for _, path := range files {
+		doc, err := metadata.OpenReadOnly(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", path, err)
+			continue
+		}
+		if matchesAll(doc.GetMetadata(), terms) {
+			// Paths go to stdout alone so the list can be piped
+			fmt.Println(path)
+			matched++
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "🔍 %d of %d document(s) match\n", matched, len(files))
+	return nil
+}
+
+// searchTerms pairs each --field with the --contains at the same position
+func searchTerms(fields, substrings []string) ([]searchTerm, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("please provide at least one --field and --contains pair")
+	}
+	if len(fields) != len(substrings) {
+		return nil, fmt.Errorf("got %d --field and %d --contains, each --field needs a --contains", len(fields), len(substrings))
+	}
+
+	terms := make([]searchTerm, len(fields))
+	for i, field := range fields {
+		if _, err := dublincore.New().Get(field); err != nil {
+			return nil, err
+		}
+		terms[i] = searchTerm{field: field, contains: strings.ToLower(substrings[i])}
+	}
+	return terms, nil
+}
+
+// matchesAll reports whether some value of each term's field contains its
+// substring, ignoring case
+func matchesAll(dc *dublincore.DublinCore, terms []searchTerm) bool {
+	for _, term := range terms {
+		values, _ := dc.Get(term.field)
+		found := false
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(value), term.contains) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
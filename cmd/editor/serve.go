@@ -0,0 +1,29 @@
+package editor
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/eduardo-moro/metadata-editor/server"
+	"github.com/urfave/cli/v2"
+)
+
+// serveAPI runs the REST API until the server fails
+func serveAPI(c *cli.Context) error {
+	maxSize := c.Int64("max-size")
+	if maxSize <= 0 {
+		return fmt.Errorf("--max-size must be positive")
+	}
+
+	srv := &http.Server{
+		Addr:              c.String("addr"),
+		Handler:           server.NewHandler(maxSize << 20),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      time.Minute,
+	}
+
+	fmt.Printf("🌐 Serving metadata API on %s (POST/PUT /metadata, max %d MB)\n", srv.Addr, maxSize)
+	return srv.ListenAndServe()
+}
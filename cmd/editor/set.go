@@ -0,0 +1,268 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/eduardo-moro/metadata-editor/docx"
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// officeFlags maps the set flags to the Office core properties they change
+var officeFlags = []struct {
+	name, property string
+}{
+	{"content-status", "contentStatus"},
+	{"revision", "revision"},
+	{"last-modified-by", "lastModifiedBy"},
+	{"doc-version", "version"},
+}
+
+// setMetadata updates only the fields passed as flags and saves without the TUI
+func setMetadata(c *cli.Context) error {
+	filePath := c.String("file")
+
+	if err := validateFileExists(filePath); err != nil {
+		return err
+	}
+
+	// Read the piped metadata first so invalid JSON fails before the document is touched
+	var piped *dublincore.DublinCore
+	if c.Bool("stdin-json") {
+		var err error
+		if piped, err = readStdinMetadata(); err != nil {
+			return err
+		}
+	}
+
+	doc, err := metadata.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open document: %w", err)
+	}
+	dc := doc.GetMetadata()
+	before := stateOf(doc)
+
+	// Flags are more specific than the piped object, so they are applied after it
+	if piped != nil {
+		dc.Merge(piped, true)
+	}
+
+	if c.IsSet("title") && c.Bool("force-title") {
+		return fmt.Errorf("--title and --force-title both set the title; use only one")
+	}
+	if c.IsSet("title") {
+		dc.SetTitle(c.String("title"))
+	}
+	if c.Bool("auto-title") || c.Bool("force-title") {
+		if err := autoTitle(doc, c.Bool("force-title"), quietFrom(c)); err != nil {
+			return err
+		}
+	}
+	if c.IsSet("creator") {
+		dc.Creator = splitList(c.String("creator"))
+	}
+	if c.IsSet("subject") {
+		dc.Subject = splitList(c.String("subject"))
+	}
+	if c.IsSet("publisher") {
+		dc.Publisher = splitList(c.String("publisher"))
+	}
+	if c.IsSet("contributor") {
+		dc.Contributor = splitList(c.String("contributor"))
+	}
+	if c.IsSet("keywords") {
+		dc.Keywords = splitList(c.String("keywords"))
+	}
+	if c.IsSet("description") {
+		dc.SetDescription(c.String("description"))
+	}
+
+	if c.IsSet("identifier") {
+		dc.Identifier = nil
+		for _, identifier := range c.StringSlice("identifier") {
+			if err := addIdentifier(dc, identifier); err != nil {
+				return err
+			}
+		}
+	}
+	if c.IsSet("format") {
+		dc.SetFormat(strings.TrimSpace(c.String("format")))
+	}
+	if c.IsSet("source") {
+		dc.Source = nil
+		for _, source := range c.StringSlice("source") {
+			dc.AddSource(source)
+		}
+	}
+	if c.IsSet("type") {
+		if !dc.SetType(c.String("type")) {
+			if c.Bool("strict-type") {
+				return fmt.Errorf("type %q is not in the DCMI Type Vocabulary (%s)", c.String("type"), strings.Join(dublincore.DCMITypes(), ", "))
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  Type %q is not in the DCMI Type Vocabulary, storing it as is\n", c.String("type"))
+		}
+	}
+	if c.IsSet("coverage") {
+		dc.SetCoverage(strings.TrimSpace(c.String("coverage")))
+	}
+	if c.IsSet("license") {
+		if !dc.SetLicense(c.String("license")) {
+			fmt.Fprintf(os.Stderr, "⚠️  Unknown license %q, storing it as the rights statement\n", c.String("license"))
+		}
+	}
+	if c.IsSet("date") {
+		if err := setDate(dc, c.String("date")); err != nil {
+			return err
+		}
+	}
+
+	if c.IsSet("company") || c.IsSet("manager") {
+		d, ok := doc.(*docx.DOCX)
+		if !ok || d.App == nil {
+			return fmt.Errorf("document has no docProps/app.xml to store company or manager")
+		}
+		if c.IsSet("company") {
+			d.App.Company = strings.TrimSpace(c.String("company"))
+		}
+		if c.IsSet("manager") {
+			d.App.Manager = strings.TrimSpace(c.String("manager"))
+		}
+	}
+
+	for _, flag := range officeFlags {
+		if !c.IsSet(flag.name) {
+			continue
+		}
+		d, ok := doc.(*docx.DOCX)
+		if !ok {
+			return fmt.Errorf("--%s is only supported for DOCX, PPTX and XLSX files", flag.name)
+		}
+		d.Office.Set(flag.property, strings.TrimSpace(c.String(flag.name)))
+	}
+
+	opts := saveOptionsFrom(c)
+	if opts.DryRun {
+		return printDryRun(doc, opts)
+	}
+
+	// An unchanged document is only written when it goes somewhere else
+	same := unchanged(doc, before, opts)
+	if same && !c.IsSet("output") {
+		opts.status("✅ No changes made. File remains unchanged.\n")
+		return errNoChanges
+	}
+
+	outputPath, err := saveDocument(doc, filePath, c.String("output"), opts)
+	if err != nil {
+		return err
+	}
+	if same {
+		return errNoChanges
+	}
+	if outputPath == stdoutPath {
+		// Keep the stream clean: the document is the only output
+		return nil
+	}
+
+	if !opts.Quiet {
+		fmt.Printf("✅ Metadata updated successfully in %s\n", outputPath)
+		printMetadata(dc)
+	}
+
+	return nil
+}
+
+// autoTitle sets the title from the document's first Heading 1, or first
+// paragraph, when the title is empty or force is set
+func autoTitle(doc metadata.Document, force, quiet bool) error {
+	dc := doc.GetMetadata()
+	if !force && strings.TrimSpace(strings.Join(dc.Title, "")) != "" {
+		return nil
+	}
+
+	d, ok := doc.(*docx.DOCX)
+	if !ok {
+		return fmt.Errorf("--auto-title is only supported for Word documents")
+	}
+	heading, err := d.HeadingText()
+	if err != nil {
+		return fmt.Errorf("failed to read the document text: %w", err)
+	}
+	if heading == "" {
+		fmt.Fprintln(os.Stderr, "⚠️  The document has no text to take a title from")
+		return nil
+	}
+
+	dc.SetTitle(heading)
+	if !quiet {
+		fmt.Printf("📝 Title taken from the document: %s\n", heading)
+	}
+	return nil
+}
+
+// completeSet suggests the DCMI Type terms after --type and the flags otherwise
+func completeSet(c *cli.Context) {
+	// The shell passes the words typed so far, then the completion flag
+	if n := len(os.Args); n >= 3 && os.Args[n-2] == "--type" {
+		for _, term := range dublincore.DCMITypes() {
+			fmt.Println(term)
+		}
+		return
+	}
+	cli.DefaultCompleteWithFlags(c.Command)(c)
+}
+
+// splitList splits a comma-separated flag value, trimming entries and dropping empty ones
+func splitList(value string) []string {
+	values := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	return values
+}
+
+// addIdentifier adds an --identifier value given as scheme:value. A URN is
+// passed whole, since its urn: prefix is part of the identifier itself.
+func addIdentifier(dc *dublincore.DublinCore, identifier string) error {
+	scheme, value, ok := strings.Cut(strings.TrimSpace(identifier), ":")
+	if !ok {
+		return fmt.Errorf("invalid --identifier %q, expected scheme:value (e.g. doi:10.1000/xyz)", identifier)
+	}
+	if strings.EqualFold(scheme, dublincore.SchemeURN) {
+		value = identifier
+	}
+	return dc.AddIdentifier(scheme, value)
+}
+
+// setDate stores a --date value in dc, as a plain date only when it was given as one
+func setDate(dc *dublincore.DublinCore, value string) error {
+	date, dateOnly, err := parseDate(value)
+	if err != nil {
+		return err
+	}
+	if dateOnly {
+		dc.SetDay(date)
+	} else {
+		dc.SetDate(date)
+	}
+	return nil
+}
+
+// parseDate parses a --date value given as YYYY-MM-DD or RFC3339, reporting
+// whether it was a plain date
+func parseDate(value string) (date time.Time, dateOnly bool, err error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, true, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC3339 (e.g. 2024-01-31T09:00:00Z)", value)
+}
@@ -0,0 +1,74 @@
+package editor
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+)
+
+func TestSetDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"2024-01-31", "2024-01-31"},
+		{" 2024-01-31 ", "2024-01-31"},
+		{"2024-01-31T09:00:00Z", "2024-01-31T09:00:00Z"},
+		{"2024-01-31T00:00:00Z", "2024-01-31T00:00:00Z"},
+		{"2024-01-31T00:00:00+05:00", "2024-01-31T00:00:00+05:00"},
+		{"2024-01-31T23:30:00-03:00", "2024-01-31T23:30:00-03:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			dc := &dublincore.DublinCore{}
+			if err := setDate(dc, tt.value); err != nil {
+				t.Fatalf("setDate: %v", err)
+			}
+			if !slices.Equal(dc.Date, []string{tt.want}) {
+				t.Errorf("Date = %q, want [%s]", dc.Date, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "31/01/2024", "2024-02-30", "2024-01-31T09:00", "2024-01-31 09:00:00Z"} {
+		dc := &dublincore.DublinCore{Date: []string{"2020-01-01"}}
+		if err := setDate(dc, value); err == nil {
+			t.Errorf("setDate(%q) succeeded, want an error", value)
+		}
+		if !slices.Equal(dc.Date, []string{"2020-01-01"}) {
+			t.Errorf("setDate(%q) changed Date to %q", value, dc.Date)
+		}
+	}
+}
+
+func TestAddIdentifier(t *testing.T) {
+	tests := []struct {
+		flag string
+		want string
+	}{
+		{"urn:isbn:9780306406157", "urn:isbn:9780306406157"},
+		{"urn:uuid:6e8bc430-9c3a-11d9-9669-0800200c9a66", "urn:uuid:6e8bc430-9c3a-11d9-9669-0800200c9a66"},
+		{"isbn:978-0-306-40615-7", "urn:isbn:9780306406157"},
+		{"doi:10.1000/xyz", "doi:10.1000/xyz"},
+		{"doi:https://doi.org/10.1000/xyz", "doi:10.1000/xyz"},
+		{"uri:https://example.com/cv", "https://example.com/cv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			dc := &dublincore.DublinCore{}
+			if err := addIdentifier(dc, tt.flag); err != nil {
+				t.Fatalf("addIdentifier: %v", err)
+			}
+			if !slices.Equal(dc.Identifier, []string{tt.want}) {
+				t.Errorf("Identifier = %q, want [%s]", dc.Identifier, tt.want)
+			}
+		})
+	}
+
+	for _, flag := range []string{"9780306406157", "urn:isbn:9780306406158", "isbn:123", "doi:10.1000"} {
+		if err := addIdentifier(&dublincore.DublinCore{}, flag); err == nil {
+			t.Errorf("addIdentifier(%q) succeeded, want an error", flag)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/metadata"
+	"github.com/urfave/cli/v2"
+)
+
+// fieldCount is how many readable documents have a field populated
+type fieldCount struct {
+	Field     string `json:"field"`
+	Populated int    `json:"populated"`
+}
+
+// metadataStats summarizes field usage across a directory
+type metadataStats struct {
+	Documents  int          `json:"documents"`
+	Readable   int          `json:"readable"`
+	Unreadable int          `json:"unreadable"`
+	Fields     []fieldCount `json:"fields"`
+}
+
+// statsMetadata prints how many documents in a directory have each field populated
+func statsMetadata(c *cli.Context) error {
+	files, err := findDocuments(c.String("dir"))
+	if err != nil {
+		return err
+	}
+
+	stats := collectStats(files)
+
+	if c.Bool("json") {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode stats: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("📊 %d document(s) scanned, %d unreadable\n\n", stats.Documents, stats.Unreadable)
+	for _, count := range stats.Fields {
+		fmt.Printf("%-12s %d/%d documents\n", count.Field+":", count.Populated, stats.Readable)
+	}
+	return nil
+}
+
+// collectStats counts the populated fields of every readable file
+func collectStats(files []string) metadataStats {
+	fields := dublincore.FieldNames()
+	stats := metadataStats{
+		Documents: len(files),
+		Fields:    make([]fieldCount, len(fields)),
+	}
+	for i, field := range fields {
+		stats.Fields[i].Field = field
+	}
+
+	for _, path := range files {
+		doc, err := metadata.OpenReadOnly(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", path, err)
+			stats.Unreadable++
+			continue
+		}
+		stats.Readable++
+
+		dc := doc.GetMetadata()
+		for i, field := range fields {
+			if values, _ := dc.Get(field); hasValue(values) {
+				stats.Fields[i].Populated++
+			}
+		}
+	}
+	return stats
+}
+
+// hasValue reports whether any of values is non-empty
+func hasValue(values []string) bool {
+	for _, value := range values {
+		if value != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package editor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eduardo-moro/metadata-editor/metadata"
+)
+
+const (
+	// stdinPath is the file name that stands for standard input
+	stdinPath = "-"
+	// stdoutPath is the --output value that streams the document to standard output
+	stdoutPath = "-"
+)
+
+// gzipMagic starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// openStdin reads a whole document from stdin, decompressing it first when
+// it arrives gzipped, and opens it in memory
+func openStdin() (metadata.Document, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stdin: %w", err)
+	}
+
+	if bytes.HasPrefix(data, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip input: %w", err)
+		}
+		if data, err = io.ReadAll(reader); err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip input: %w", err)
+		}
+	}
+
+	return metadata.OpenBytes(data)
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
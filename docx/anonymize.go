@@ -0,0 +1,47 @@
+package docx
+
+import "fmt"
+
+// Anonymize removes the properties that identify who wrote or edited the
+// document: creators and contributors, cp:lastModifiedBy and cp:revision in
+// core.xml, Company and Manager in app.xml and every custom property. The
+// document content is left untouched. It returns one line per removed value.
+func (d *DOCX) Anonymize() []string {
+	var removed []string
+
+	for _, field := range []string{"creator", "contributor"} {
+		values, _ := d.DublinCore.Get(field)
+		for _, value := range values {
+			removed = append(removed, fmt.Sprintf("dc:%s %q", field, value))
+		}
+		d.DublinCore.Set(field, nil)
+	}
+
+	for _, name := range []string{"lastModifiedBy", "revision"} {
+		field := d.Office.field(name)
+		if *field != "" {
+			removed = append(removed, fmt.Sprintf("cp:%s %q", name, *field))
+			*field = ""
+		}
+	}
+
+	if d.App != nil {
+		for _, name := range []string{"Company", "Manager"} {
+			field := d.App.field(name)
+			if *field != "" {
+				removed = append(removed, fmt.Sprintf("app.xml %s %q", name, *field))
+				*field = ""
+			}
+		}
+	}
+
+	for _, property := range d.CustomProperties() {
+		removed = append(removed, fmt.Sprintf("custom property %s %q", property.Name, property.Value))
+	}
+	if len(d.custom) > 0 {
+		d.custom = nil
+		d.customChanged = true
+	}
+
+	return removed
+}
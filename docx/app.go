@@ -0,0 +1,102 @@
+package docx
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/eduardo-moro/metadata-editor/internal/rawxml"
+)
+
+const (
+	appPropertiesPath = "docProps/app.xml"
+
+	extendedPropertiesNamespace = "http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"
+	docPropsVTypesNamespace     = "http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"
+)
+
+// AppProperties represents the extended properties stored in docProps/app.xml
+type AppProperties struct {
+	Application string
+	Company     string
+	Manager     string
+
+	// elements holds every element of the original part, in order, so
+	// unmodeled ones (Template, HeadingPairs, ...) survive a Save
+	elements []rawxml.Element
+}
+
+// appPropertiesXML is the serialized form of app.xml
+type appPropertiesXML struct {
+	XMLName  xml.Name         `xml:"Properties"`
+	XMLNS    string           `xml:"xmlns,attr"`
+	XMLNSVT  string           `xml:"xmlns:vt,attr"`
+	Elements []rawxml.Element `xml:",any"`
+}
+
+// parseAppXML parses docProps/app.xml, keeping unknown elements for round-trip
+func parseAppXML(data []byte) (*AppProperties, error) {
+	var parsed appPropertiesXML
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("XML parsing failed: %w", err)
+	}
+
+	app := &AppProperties{elements: parsed.Elements}
+	for _, element := range parsed.Elements {
+		if field := app.field(element.XMLName.Local); field != nil {
+			if text, err := element.Text(); err == nil {
+				*field = text
+			}
+		}
+	}
+
+	return app, nil
+}
+
+// ToXML converts AppProperties to XML, replacing the modeled elements in place
+func (app *AppProperties) ToXML() ([]byte, error) {
+	prefixes := map[string]string{
+		extendedPropertiesNamespace: "",
+		docPropsVTypesNamespace:     "vt",
+	}
+
+	var fields []rawxml.Field
+	for _, name := range []string{"Application", "Manager", "Company"} {
+		field := rawxml.Field{
+			Name:    xml.Name{Space: extendedPropertiesNamespace, Local: name},
+			Literal: name,
+		}
+		if value := *app.field(name); value != "" {
+			field.Values = []string{value}
+		}
+		fields = append(fields, field)
+	}
+
+	out := &appPropertiesXML{
+		XMLNS:   extendedPropertiesNamespace,
+		XMLNSVT: docPropsVTypesNamespace,
+	}
+	for _, element := range rawxml.Merge(app.elements, fields) {
+		out.Elements = append(out.Elements, element.WithPrefixes(prefixes))
+	}
+
+	header := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(header + string(data)), nil
+}
+
+// field maps an app.xml element name to the matching struct field
+func (app *AppProperties) field(name string) *string {
+	switch name {
+	case "Application":
+		return &app.Application
+	case "Company":
+		return &app.Company
+	case "Manager":
+		return &app.Manager
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package docx
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// officeExtensions are the file extensions ProcessDir picks up
+var officeExtensions = map[string]bool{
+	".docx": true,
+	".pptx": true,
+	".xlsx": true,
+}
+
+// Summary reports the outcome of ProcessDir
+type Summary struct {
+	Processed int              // files opened and passed to the callback
+	Saved     int              // files rewritten because their metadata changed
+	Errors    map[string]error // files left untouched, by path
+}
+
+// Failed returns the number of files that couldn't be processed
+func (s Summary) Failed() int {
+	return len(s.Errors)
+}
+
+// ProcessDir opens every DOCX, PPTX and XLSX file under dir, in lexical order,
+// and calls fn to edit its metadata. A file is saved in place only if fn
+// returns nil and it changed the metadata, the Office properties, the app.xml
+// properties or the custom properties; when fn fails, the file is skipped
+// and the error recorded in the summary.
+func ProcessDir(dir string, fn func(*DOCX) error) Summary {
+	summary := Summary{Errors: map[string]error{}}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			summary.Errors[path] = err
+			return nil
+		}
+		if d.IsDir() || !officeExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+
+		doc, err := Open(path)
+		if err != nil {
+			summary.Errors[path] = err
+			return nil
+		}
+		summary.Processed++
+
+		before := snapshot(doc)
+		if err := fn(doc); err != nil {
+			summary.Errors[path] = err
+			return nil
+		}
+		if !before.changed(doc) {
+			return nil
+		}
+		if err := doc.Save(""); err != nil {
+			summary.Errors[path] = fmt.Errorf("failed to save: %w", err)
+			return nil
+		}
+		summary.Saved++
+		return nil
+	})
+	if err != nil {
+		summary.Errors[dir] = err
+	}
+
+	return summary
+}
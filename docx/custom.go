@@ -0,0 +1,247 @@
+package docx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eduardo-moro/metadata-editor/internal/rawxml"
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+)
+
+const (
+	customPropertiesPath = "docProps/custom.xml"
+
+	customPropertiesNamespace   = "http://schemas.openxmlformats.org/officeDocument/2006/custom-properties"
+	customPropertiesContentType = "application/vnd.openxmlformats-officedocument.custom-properties+xml"
+	customRelationshipType      = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/custom-properties"
+	customRelationshipSuffix    = "/relationships/custom-properties"
+
+	// customPropertyFormatID is the FMTID Word uses for user-defined properties
+	customPropertyFormatID = "{D5CDD505-2E9C-101B-9397-08002B2CF9AE}"
+
+	// firstCustomPropertyID is the lowest pid allowed for a custom property
+	firstCustomPropertyID = 2
+)
+
+// CustomProperty is a named property from docProps/custom.xml
+type CustomProperty struct {
+	Name  string
+	Type  string // docPropsVTypes element: lpwstr, i4, i8, r8, bool, filetime, ...
+	Value string // text of the value element
+}
+
+// customProperty is a custom property as stored in the part
+type customProperty struct {
+	Attrs []xml.Attr     `xml:",any,attr"` // fmtid, linkTarget, ...
+	PID   int            `xml:"pid,attr"`
+	Name  string         `xml:"name,attr"`
+	Value rawxml.Element `xml:",any"`
+}
+
+// customPropertiesXML is the serialized form of custom.xml
+type customPropertiesXML struct {
+	XMLName    xml.Name         `xml:"Properties"`
+	XMLNS      string           `xml:"xmlns,attr"`
+	XMLNSVT    string           `xml:"xmlns:vt,attr"`
+	Properties []customProperty `xml:"property"`
+}
+
+// parseCustomXML parses docProps/custom.xml
+func parseCustomXML(data []byte) ([]customProperty, error) {
+	var parsed customPropertiesXML
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("XML parsing failed: %w", err)
+	}
+	return parsed.Properties, nil
+}
+
+// readCustomProperties reads the custom properties part, returning its entry
+// name ("" when the document has none). An unreadable part yields no
+// properties and is copied unchanged unless they are edited.
+func readCustomProperties(reader *zip.Reader) ([]customProperty, string) {
+	file, err := ziputil.FindFile(reader, partPath(reader, customRelationshipSuffix, customPropertiesPath))
+	if err != nil {
+		return nil, ""
+	}
+	data, err := ziputil.ReadFile(file)
+	if err != nil {
+		logger().Warn("ignoring unreadable custom properties", "part", file.Name, "error", err)
+		return nil, file.Name
+	}
+	properties, err := parseCustomXML(data)
+	if err != nil {
+		logger().Warn("ignoring malformed custom properties", "part", file.Name, "error", err)
+		return nil, file.Name
+	}
+	return properties, file.Name
+}
+
+// customXML serializes the custom properties into docProps/custom.xml
+func customXML(properties []customProperty) ([]byte, error) {
+	prefixes := map[string]string{
+		customPropertiesNamespace: "",
+		docPropsVTypesNamespace:   "vt",
+	}
+
+	out := &customPropertiesXML{
+		XMLNS:   customPropertiesNamespace,
+		XMLNSVT: docPropsVTypesNamespace,
+	}
+	for _, property := range properties {
+		written := customProperty{
+			Name:  property.Name,
+			PID:   property.PID,
+			Value: property.Value.WithPrefixes(prefixes),
+		}
+		for _, attr := range property.Attrs {
+			written.Attrs = append(written.Attrs, rawxml.QualifyAttr(attr, prefixes))
+		}
+		out.Properties = append(out.Properties, written)
+	}
+
+	header := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(header + string(data)), nil
+}
+
+// writeCustomProperties writes docProps/custom.xml, replacing src or as a new entry when src is nil
+func (d *DOCX) writeCustomProperties(zipWriter *zip.Writer, src *zip.File) error {
+	var customWriter io.Writer
+	var err error
+	if src != nil {
+		customWriter, err = ziputil.CreateRegenerated(zipWriter, src, d.Deterministic)
+	} else {
+		customWriter, err = ziputil.CreateNew(zipWriter, customPropertiesPath, d.Deterministic)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create custom.xml: %w", err)
+	}
+
+	data, err := customXML(d.custom)
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom properties: %w", err)
+	}
+
+	_, err = customWriter.Write(data)
+	return err
+}
+
+// CustomProperties returns the custom properties in document order
+func (d *DOCX) CustomProperties() []CustomProperty {
+	properties := make([]CustomProperty, 0, len(d.custom))
+	for _, property := range d.custom {
+		value, _ := property.Value.Text()
+		properties = append(properties, CustomProperty{
+			Name:  property.Name,
+			Type:  property.Value.XMLName.Local,
+			Value: value,
+		})
+	}
+	return properties
+}
+
+// CustomProperty returns the custom property with the given name, ignoring case as Word does
+func (d *DOCX) CustomProperty(name string) (CustomProperty, bool) {
+	for _, property := range d.CustomProperties() {
+		if strings.EqualFold(property.Name, name) {
+			return property, true
+		}
+	}
+	return CustomProperty{}, false
+}
+
+// SetCustomProperty adds or replaces a custom property. The value must be a
+// string, bool, integer, float or time.Time; its Go type picks the stored type.
+func (d *DOCX) SetCustomProperty(name string, value any) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("custom property name is empty")
+	}
+
+	element, err := customValue(value)
+	if err != nil {
+		return fmt.Errorf("custom property %q: %w", name, err)
+	}
+
+	for i := range d.custom {
+		if strings.EqualFold(d.custom[i].Name, name) {
+			d.custom[i].Value = element
+			d.customChanged = true
+			return nil
+		}
+	}
+
+	pid := firstCustomPropertyID
+	for _, property := range d.custom {
+		if property.PID >= pid {
+			pid = property.PID + 1
+		}
+	}
+	d.custom = append(d.custom, customProperty{
+		Name:  name,
+		PID:   pid,
+		Attrs: []xml.Attr{{Name: xml.Name{Local: "fmtid"}, Value: customPropertyFormatID}},
+		Value: element,
+	})
+	d.customChanged = true
+	return nil
+}
+
+// DeleteCustomProperty removes a custom property, reporting whether it existed
+func (d *DOCX) DeleteCustomProperty(name string) bool {
+	for i, property := range d.custom {
+		if strings.EqualFold(property.Name, name) {
+			d.custom = append(d.custom[:i], d.custom[i+1:]...)
+			d.customChanged = true
+			return true
+		}
+	}
+	return false
+}
+
+// customValue builds the vt: value element for a Go value
+func customValue(value any) (rawxml.Element, error) {
+	var vtType, text string
+	switch v := value.(type) {
+	case string:
+		vtType, text = "lpwstr", v
+	case bool:
+		vtType, text = "bool", strconv.FormatBool(v)
+	case int:
+		vtType, text = integerType(int64(v)), strconv.Itoa(v)
+	case int32:
+		vtType, text = "i4", strconv.FormatInt(int64(v), 10)
+	case int64:
+		vtType, text = integerType(v), strconv.FormatInt(v, 10)
+	case float32:
+		vtType, text = "r8", strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		vtType, text = "r8", strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		vtType, text = "filetime", v.UTC().Format("2006-01-02T15:04:05Z")
+	default:
+		return rawxml.Element{}, fmt.Errorf("unsupported value type %T", value)
+	}
+
+	element := rawxml.TextElement(vtType, text)
+	element.XMLName.Space = docPropsVTypesNamespace
+	return element, nil
+}
+
+// integerType returns the smallest vt integer type holding v
+func integerType(v int64) string {
+	if v >= math.MinInt32 && v <= math.MaxInt32 {
+		return "i4"
+	}
+	return "i8"
+}
@@ -4,23 +4,89 @@ import (
 	"archive/zip"
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/internal/fileutil"
+	"github.com/eduardo-moro/metadata-editor/internal/rawxml"
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
 )
 
 const (
-	corePropertiesPath = "docProps/core.xml"
+	corePropertiesPath        = "docProps/core.xml"
+	corePropertiesContentType = "application/vnd.openxmlformats-package.core-properties+xml"
+
+	corePropertiesNamespace = "http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+	dcElementsNamespace     = "http://purl.org/dc/elements/1.1/"
+	dcTermsNamespace        = "http://purl.org/dc/terms/"
+	xsiNamespace            = "http://www.w3.org/2001/XMLSchema-instance"
 )
 
-// DOCX represents a DOCX document with Dublin Core metadata
+// errReadOnly is returned when saving a document opened with OpenReadOnly
+var errReadOnly = errors.New("document was opened read-only")
+
+// ErrInvalidPackage is returned when a zip file lacks the parts of an Office document
+var ErrInvalidPackage = errors.New("not a valid Office Open XML package")
+
+// corePrefixes are the literal prefixes declared on the written core.xml root
+var corePrefixes = map[string]string{
+	corePropertiesNamespace: "cp",
+	dcElementsNamespace:     "dc",
+	dcTermsNamespace:        "dcterms",
+	xsiNamespace:            "xsi",
+}
+
+// DOCX represents an Office Open XML document (DOCX, PPTX or XLSX) with Dublin Core metadata
 type DOCX struct {
 	FilePath   string
 	DublinCore *dublincore.DublinCore
-	FileData   []byte // Store the file content in memory
+	App        *AppProperties // nil when the document has no docProps/app.xml
+	FileData   []byte         // Store the file content in memory
+
+	// Marshal sets the byte layout of the written core.xml; nil uses
+	// DefaultMarshalOptions
+	Marshal *MarshalOptions
+
+	// Deterministic makes Save produce byte-identical output for the same
+	// input and metadata: entries keep their original order and compression,
+	// every entry time is set to 1980-01-01 and timestamp extra fields are dropped
+	Deterministic bool
+
+	// coreExtra holds the core.xml elements this package doesn't model
+	// (cp:revision, dcterms:created, ...) so they survive a Save
+	coreExtra []rawxml.Element
+
+	// Office holds the cp: properties of core.xml that aren't Dublin Core
+	Office OfficeProperties
+
+	// corePath is the zip entry name of the core properties part
+	corePath string
+
+	// duplicates lists the entry names that occur more than once in the archive
+	duplicates []string
+
+	// parts lists the zip entries as they were when the document was opened
+	parts []PartInfo
+
+	// mimeType is the document type detected from the package's main part
+	mimeType string
+
+	// openedDate is dc:date as read, so Save only touches dcterms:created when Date changes
+	openedDate []string
+
+	// custom holds the parsed docProps/custom.xml properties; the part is
+	// only rewritten when customChanged is set
+	custom        []customProperty
+	customPath    string // entry name of the custom part, "" when absent
+	customChanged bool
+
+	// opened is the editable state as read, for Changed
+	opened properties
 }
 
 // ... (previous imports and constants)
@@ -33,191 +99,396 @@ type CoreProperties struct {
 	XMLNSDCTERMS string   `xml:"xmlns:dcterms,attr"`
 	XMLNSXSI     string   `xml:"xmlns:xsi,attr"`
 
-	// Dublin Core fields
+	// Dublin Core fields. Title and Description hold only the default-language
+	// values: ECMA-376 Part 2 forbids xml:lang in the core properties part.
 	Title       []string `xml:"dc:title,omitempty"`
 	Creator     []string `xml:"dc:creator,omitempty"`
 	Subject     []string `xml:"dc:subject,omitempty"`
 	Description []string `xml:"dc:description,omitempty"`
-
-	// CP namespace fields
-	Keywords []string `xml:"cp:keywords,omitempty"`
+	Publisher   []string `xml:"dc:publisher,omitempty"`
+	Contributor []string `xml:"dc:contributor,omitempty"`
+	Date        []string `xml:"dc:date,omitempty"`
+	Type        []string `xml:"dc:type,omitempty"`
+	Format      []string `xml:"dc:format,omitempty"`
+	Identifier  []string `xml:"dc:identifier,omitempty"`
+	Source      []string `xml:"dc:source,omitempty"`
+	Language    []string `xml:"dc:language,omitempty"`
+	Relation    []string `xml:"dc:relation,omitempty"`
+	Coverage    []string `xml:"dc:coverage,omitempty"`
+	Rights      []string `xml:"dc:rights,omitempty"`
+
+	// CP namespace fields; Word stores keywords as a single delimited string
+	Keywords string   `xml:"cp:keywords,omitempty"`
 	Category []string `xml:"cp:category,omitempty"`
+
+	// Office properties from the OPC core properties schema
+	LastModifiedBy string `xml:"cp:lastModifiedBy,omitempty"`
+	Revision       string `xml:"cp:revision,omitempty"`
+	ContentStatus  string `xml:"cp:contentStatus,omitempty"`
+	Version        string `xml:"cp:version,omitempty"`
+
+	// Unmodeled elements copied from the original core.xml
+	Extra []rawxml.Element `xml:",any"`
 }
 
-// ToXML converts CoreProperties to XML
+// MarshalOptions controls the byte layout of a serialized core.xml
+type MarshalOptions struct {
+	// Indent is repeated once per nesting level; "" writes compact XML with
+	// every element on one line, as Word does
+	Indent string
+
+	// LineEnding follows the XML declaration; "" means "\n". Word uses "\r\n".
+	LineEnding string
+
+	// BOM prefixes the part with a UTF-8 byte order mark
+	BOM bool
+
+	// Order lists prefixed element names ("dc:title", "cp:keywords", ...) in
+	// the order they are written; unlisted elements follow in the default
+	// order. nil keeps the default, which is CoreProperties' field order.
+	Order []string
+}
+
+// DefaultMarshalOptions is the layout used when none is given: two-space
+// indentation, "\n" line endings and no byte order mark
+var DefaultMarshalOptions = MarshalOptions{Indent: "  "}
+
+// WordMarshalOptions matches the byte layout and element order of the core.xml Word writes
+var WordMarshalOptions = MarshalOptions{LineEnding: "\r\n", BOM: true, Order: WordElementOrder}
+
+// WordElementOrder is the order in which Word writes the core.xml elements it knows
+var WordElementOrder = []string{
+	"dc:title", "dc:subject", "dc:creator", "cp:keywords", "dc:description",
+	"cp:lastModifiedBy", "cp:revision", "cp:lastPrinted", "dcterms:created",
+	"dcterms:modified", "cp:category", "cp:contentStatus", "dc:language",
+	"cp:version", "dc:identifier",
+}
+
+// utf8BOM is the UTF-8 encoding of U+FEFF
+const utf8BOM = "\xef\xbb\xbf"
+
+// ToXML converts CoreProperties to XML with DefaultMarshalOptions
 func (cp *CoreProperties) ToXML() ([]byte, error) {
-	cp.XMLNSCP = "http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
-	cp.XMLNSDC = "http://purl.org/dc/elements/1.1/"
-	cp.XMLNSDCTERMS = "http://purl.org/dc/terms/"
-	cp.XMLNSXSI = "http://www.w3.org/2001/XMLSchema-instance"
+	return cp.MarshalWith(DefaultMarshalOptions)
+}
+
+// MarshalWith converts CoreProperties to XML laid out as opts describes
+func (cp *CoreProperties) MarshalWith(opts MarshalOptions) ([]byte, error) {
+	cp.XMLNSCP = corePropertiesNamespace
+	cp.XMLNSDC = dcElementsNamespace
+	cp.XMLNSDCTERMS = dcTermsNamespace
+	cp.XMLNSXSI = xsiNamespace
+
+	var v any = cp
+	if opts.Order != nil {
+		ordered, err := cp.ordered(opts.Order)
+		if err != nil {
+			return nil, err
+		}
+		v = ordered
+	}
 
-	header := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
-	data, err := xml.MarshalIndent(cp, "", "  ")
+	var data []byte
+	var err error
+	if opts.Indent == "" {
+		data, err = xml.Marshal(v)
+	} else {
+		data, err = xml.MarshalIndent(v, "", opts.Indent)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return []byte(header + string(data)), nil
+	lineEnding := opts.LineEnding
+	if lineEnding == "" {
+		lineEnding = "\n"
+	}
+
+	var buf bytes.Buffer
+	if opts.BOM {
+		buf.WriteString(utf8BOM)
+	}
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + lineEnding)
+	buf.Write(data)
+	return buf.Bytes(), nil
 }
 
-// writeCoreProperties writes properly formatted core.xml with both DC and CP fields
-func (d *DOCX) writeCoreProperties(zipWriter *zip.Writer) error {
-	coreWriter, err := zipWriter.Create(corePropertiesPath)
+// orderedCoreProperties is core.xml with its elements in an explicit order
+type orderedCoreProperties struct {
+	XMLName      xml.Name         `xml:"cp:coreProperties"`
+	XMLNSCP      string           `xml:"xmlns:cp,attr"`
+	XMLNSDC      string           `xml:"xmlns:dc,attr"`
+	XMLNSDCTERMS string           `xml:"xmlns:dcterms,attr"`
+	XMLNSXSI     string           `xml:"xmlns:xsi,attr"`
+	Elements     []rawxml.Element `xml:",any"`
+}
+
+// ordered returns the elements of cp sorted by their position in order,
+// keeping the field order among unlisted and repeated elements
+func (cp *CoreProperties) ordered(order []string) (*orderedCoreProperties, error) {
+	data, err := xml.Marshal(cp)
 	if err != nil {
-		return fmt.Errorf("failed to create core.xml: %w", err)
+		return nil, err
+	}
+	var parsed struct {
+		Elements []rawxml.Element `xml:",any"`
+	}
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		return nil, err
 	}
 
-	// Create CoreProperties struct with both DC and CP fields
-	coreProps := &CoreProperties{
-		Title:       d.DublinCore.Title,
-		Creator:     d.DublinCore.Creator,
-		Subject:     d.DublinCore.Subject,
-		Description: d.DublinCore.Description,
-		Keywords:    d.DublinCore.Keywords,
-		Category:    d.DublinCore.Category,
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		rank[name] = i
+	}
+	position := func(element rawxml.Element) int {
+		if i, ok := rank[element.XMLName.Local]; ok {
+			return i
+		}
+		return len(order)
 	}
 
-	data, err := coreProps.ToXML()
-	if err != nil {
-		return fmt.Errorf("failed to marshal core properties: %w", err)
+	elements := make([]rawxml.Element, len(parsed.Elements))
+	for i, element := range parsed.Elements {
+		elements[i] = element.WithPrefixes(corePrefixes)
 	}
+	slices.SortStableFunc(elements, func(a, b rawxml.Element) int {
+		return position(a) - position(b)
+	})
+
+	return &orderedCoreProperties{
+		XMLNSCP:      cp.XMLNSCP,
+		XMLNSDC:      cp.XMLNSDC,
+		XMLNSDCTERMS: cp.XMLNSDCTERMS,
+		XMLNSXSI:     cp.XMLNSXSI,
+		Elements:     elements,
+	}, nil
+}
 
-	if _, err := coreWriter.Write(data); err != nil {
-		return fmt.Errorf("failed to write core properties: %w", err)
+// marshalOptions returns the layout Save uses for core.xml
+func (d *DOCX) marshalOptions() MarshalOptions {
+	if d.Marshal != nil {
+		return *d.Marshal
 	}
+	return DefaultMarshalOptions
+}
 
-	return nil
+// CoreXML returns the core.xml that Save writes for the current metadata and Marshal layout
+func (d *DOCX) CoreXML() ([]byte, error) {
+	data, err := d.CoreProperties().MarshalWith(d.marshalOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal core properties: %w", err)
+	}
+	return data, nil
 }
 
-// parseCoreXML parses standard DOCX core.xml with proper namespace handling
-func parseCoreXML(data []byte) (*dublincore.DublinCore, error) {
-	// First, try to parse with proper namespace handling
-	var coreProps struct {
-		XMLName     xml.Name `xml:"coreProperties"`
-		Title       []string `xml:"title"`
-		Creator     []string `xml:"creator"`
-		Subject     []string `xml:"subject"`
-		Description []string `xml:"description"`
-		Keywords    []string `xml:"keywords"`
-		Category    []string `xml:"category"`
+// writeCoreProperties writes properly formatted core.xml with both DC and CP
+// fields, replacing src or as a new entry at corePath when src is nil
+func (d *DOCX) writeCoreProperties(zipWriter *zip.Writer, src *zip.File) error {
+	var coreWriter io.Writer
+	var err error
+	if src != nil {
+		coreWriter, err = ziputil.CreateRegenerated(zipWriter, src, d.Deterministic)
+	} else {
+		coreWriter, err = ziputil.CreateNew(zipWriter, d.corePath, d.Deterministic)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create core.xml: %w", err)
 	}
 
-	if err := xml.Unmarshal(data, &coreProps); err != nil {
-		return nil, fmt.Errorf("XML parsing failed: %w", err)
+	data, err := d.CoreXML()
+	if err != nil {
+		return err
 	}
 
-	dc := dublincore.New()
+	if _, err := coreWriter.Write(data); err != nil {
+		return fmt.Errorf("failed to write core properties: %w", err)
+	}
+
+	return nil
+}
 
-	// Map the core properties to Dublin Core
-	if len(coreProps.Title) > 0 {
-		dc.Title = coreProps.Title
+// CoreProperties builds the core.xml content that Save writes for the current
+// metadata, with values passed through dublincore.SanitizeValue. Language-tagged
+// titles and descriptions are left out, since core.xml can't carry xml:lang.
+func (d *DOCX) CoreProperties() *CoreProperties {
+	dc := d.DublinCore.Sanitized()
+	coreProps := &CoreProperties{
+		Title:       dc.Title,
+		Creator:     dc.Creator,
+		Subject:     dc.Subject,
+		Description: dc.Description,
+		Publisher:   dc.Publisher,
+		Contributor: dc.Contributor,
+		Date:        dc.Date,
+		Type:        dc.Type,
+		Format:      d.explicitFormat(dc),
+		Identifier:  dc.Identifier,
+		Source:      dc.Source,
+		Language:    dc.Language,
+		Relation:    dc.Relation,
+		Coverage:    dc.Coverage,
+		Rights:      dc.Rights,
+		Keywords:    strings.Join(dc.Keywords, ", "),
+		Category:    dc.Category,
+
+		LastModifiedBy: d.Office.LastModifiedBy,
+		Revision:       d.Office.Revision,
+		ContentStatus:  d.Office.ContentStatus,
+		Version:        d.Office.Version,
 	}
-	if len(coreProps.Creator) > 0 {
-		dc.Creator = coreProps.Creator
+	extra := d.coreExtra
+	if created, ok := d.createdElement(); ok {
+		extra = replaceElement(extra, created)
 	}
-	if len(coreProps.Subject) > 0 {
-		dc.Subject = coreProps.Subject
+	for _, element := range extra {
+		coreProps.Extra = append(coreProps.Extra, element.WithPrefixes(corePrefixes))
 	}
-	if len(coreProps.Description) > 0 {
-		dc.Description = coreProps.Description
+	return coreProps
+}
+
+// explicitFormat returns the dc:format values to write: the format read from
+// core.xml or set explicitly, but not the document type Format defaults to,
+// which Office infers from the package and doesn't expect in core.xml
+func (d *DOCX) explicitFormat(dc *dublincore.DublinCore) []string {
+	if dc.FormatSet() || !slices.Equal(dc.Format, []string{d.mimeType}) {
+		return dc.Format
 	}
-	if len(coreProps.Keywords) > 0 {
-		dc.Keywords = coreProps.Keywords
+	return nil
+}
+
+// writeAppProperties writes docProps/app.xml with the updated extended properties
+func (d *DOCX) writeAppProperties(zipWriter *zip.Writer, src *zip.File) error {
+	appWriter, err := ziputil.CreateRegenerated(zipWriter, src, d.Deterministic)
+	if err != nil {
+		return fmt.Errorf("failed to create app.xml: %w", err)
 	}
-	if len(coreProps.Category) > 0 {
-		dc.Category = coreProps.Category
+
+	data, err := d.App.ToXML()
+	if err != nil {
+		return fmt.Errorf("failed to marshal app properties: %w", err)
 	}
 
-	// If we found any data, return it
-	if len(dc.Title) > 0 || len(dc.Creator) > 0 || len(dc.Keywords) > 0 || len(dc.Description) > 0 {
-		return dc, nil
+	if _, err := appWriter.Write(data); err != nil {
+		return fmt.Errorf("failed to write app properties: %w", err)
 	}
 
-	// If no data found with direct parsing, try alternative approach
-	// Sometimes the XML uses different namespace prefixes
-	return parseCoreXMLAlternative(data)
+	return nil
 }
 
-// parseCoreXMLAlternative tries alternative parsing approaches
-func parseCoreXMLAlternative(data []byte) (*dublincore.DublinCore, error) {
-	dc := dublincore.New()
+// createdElement returns the dcterms:created element Word displays as the
+// creation date, built from a Date changed since the document was opened.
+// Word only reads it with xsi:type="dcterms:W3CDTF" and a full UTC date-time.
+func (d *DOCX) createdElement() (rawxml.Element, bool) {
+	date := d.DublinCore.Date
+	if len(date) == 0 || slices.Equal(date, d.openedDate) {
+		return rawxml.Element{}, false
+	}
+	t, ok := dublincore.ParseW3CDTF(date[0])
+	if !ok {
+		return rawxml.Element{}, false
+	}
 
-	// Convert to string for manual inspection
-	xmlStr := string(data)
+	element := rawxml.TextElement("created", t.UTC().Format("2006-01-02T15:04:05Z"))
+	element.XMLName.Space = dcTermsNamespace
+	element.Attrs = []xml.Attr{{Name: xml.Name{Space: xsiNamespace, Local: "type"}, Value: "dcterms:W3CDTF"}}
+	return element, true
+}
 
-	// Try to extract fields using string manipulation as fallback
-	extractField := func(tag string) []string {
-		var values []string
-		startTag := "<" + tag + ">"
-		endTag := "</" + tag + ">"
+// replaceElement replaces the first element with the same name as element,
+// appending it when there is none
+func replaceElement(elements []rawxml.Element, element rawxml.Element) []rawxml.Element {
+	out := append([]rawxml.Element{}, elements...)
+	for i := range out {
+		if out[i].Is(element.XMLName) {
+			out[i] = element
+			return out
+		}
+	}
+	return append(out, element)
+}
 
-		start := strings.Index(xmlStr, startTag)
-		for start != -1 {
-			end := strings.Index(xmlStr[start:], endTag)
-			if end == -1 {
-				break
-			}
+// parseCoreXML parses core.xml, matching elements by namespace URI so any
+// prefix (or a default xmlns) works. Elements CoreProperties doesn't model
+// are returned separately so Save can write them back.
+// A core.xml without dc:format gets format, the type of the document, which
+// Save leaves out unless it is set explicitly.
+func parseCoreXML(data []byte, format string) (*dublincore.DublinCore, OfficeProperties, []rawxml.Element, error) {
+	var office OfficeProperties
+	var parsed struct {
+		XMLName  xml.Name
+		Elements []rawxml.Element `xml:",any"`
+	}
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		if errors.Is(err, io.EOF) {
+			// encoding/xml reports a bare EOF when there is no root element at all
+			err = errors.New("no root element, the part is empty or not XML")
+		}
+		return nil, office, nil, fmt.Errorf("XML parsing failed: %w", err)
+	}
+	if parsed.XMLName.Local != "coreProperties" {
+		return nil, office, nil, fmt.Errorf("root element is %s, not cp:coreProperties", parsed.XMLName.Local)
+	}
 
-			value := xmlStr[start+len(startTag) : start+end]
-			values = append(values, strings.TrimSpace(value))
+	dc := newDublinCore(format)
+	found := map[string][]string{}
+	var extra []rawxml.Element
 
-			// Find next occurrence
-			start = strings.Index(xmlStr[start+end+len(endTag):], startTag)
-			if start != -1 {
-				start = start + end + len(endTag) // Adjust index
-			}
+	for _, element := range parsed.Elements {
+		if !isModeledCoreElement(element.XMLName) {
+			extra = append(extra, element)
+			continue
 		}
-		return values
-	}
+		text, err := element.Text()
+		if err != nil {
+			return nil, office, nil, fmt.Errorf("XML parsing failed: %s: %w", element.XMLName.Local, err)
+		}
+		text = strings.TrimSpace(text)
 
-	// Try different possible tag formats
-	possibleTags := []string{
-		"dc:title", "title", "cp:title",
-		"dc:creator", "creator", "cp:creator",
-		"dc:subject", "subject", "cp:subject",
-		"dc:description", "description", "cp:description",
-		"cp:keywords", "keywords",
-		"cp:category", "category",
+		if element.XMLName.Space == corePropertiesNamespace && isOfficeProperty(element.XMLName.Local) {
+			office.Set(element.XMLName.Local, text)
+			continue
+		}
+
+		// xml:lang isn't allowed in core.xml, so a stray one is ignored and the
+		// value read as a default-language one rather than lost on Save
+		name := element.XMLName.Local
+		found[name] = append(found[name], text)
 	}
+	found["keywords"] = splitKeywords(found["keywords"])
 
-	for _, tag := range possibleTags {
-		values := extractField(tag)
+	// Keep defaults for absent fields
+	for name, values := range found {
 		if len(values) > 0 {
-			switch tag {
-			case "dc:title", "title", "cp:title":
-				dc.Title = values
-			case "dc:creator", "creator", "cp:creator":
-				dc.Creator = values
-			case "dc:subject", "subject", "cp:subject":
-				dc.Subject = values
-			case "dc:description", "description", "cp:description":
-				dc.Description = values
-			case "cp:keywords", "keywords":
-				dc.Keywords = values
-			case "cp:category", "category":
-				dc.Category = values
-			}
+			dc.Set(name, values)
 		}
 	}
 
-	return dc, nil
+	return dc, office, extra, nil
 }
 
-// extractDublinCore extracts Dublin Core metadata from core.xml
-func extractDublinCore(data []byte) (*dublincore.DublinCore, error) {
-	// First try to parse as full core properties
-	dc, err := parseCoreXML(data)
-	if err == nil && (len(dc.Title) > 0 || len(dc.Creator) > 0 || len(dc.Keywords) > 0) {
-		return dc, nil
-	}
+// newDublinCore returns the defaults for a parsed core.xml, with format as the
+// document type. Date is left empty so opening a document doesn't stamp it
+// with the current time.
+func newDublinCore(format string) *dublincore.DublinCore {
+	dc := dublincore.New()
+	dc.Date = nil
+	dc.Format = []string{format}
+	return dc
+}
 
-	// If that fails, try to parse as raw Dublin Core
-	var rawDC dublincore.DublinCore
-	if err := xml.Unmarshal(data, &rawDC); err != nil {
-		return nil, err
+// splitKeywords splits Word's delimited cp:keywords values into separate keywords.
+// Word uses commas or semicolons depending on the locale.
+func splitKeywords(values []string) []string {
+	var keywords []string
+	for _, value := range values {
+		for _, keyword := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+			if trimmed := strings.TrimSpace(keyword); trimmed != "" {
+				keywords = append(keywords, trimmed)
+			}
+		}
 	}
-
-	return &rawDC, nil
+	return keywords
 }
 
 // Open opens a DOCX file and reads its metadata
@@ -228,29 +499,191 @@ func Open(filePath string) (*DOCX, error) {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
+	docx, err := OpenBytes(fileData)
+	if err != nil {
+		return nil, err
+	}
+	docx.FilePath = filePath
+
+	return docx, nil
+}
+
+// OpenBytes reads the metadata of a DOCX already in memory, such as an upload.
+// FilePath is left empty, so Save needs an explicit output path.
+func OpenBytes(fileData []byte) (*DOCX, error) {
 	// Create a zip reader from the file data
 	reader, err := zip.NewReader(bytes.NewReader(fileData), int64(len(fileData)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create zip reader: %w", err)
 	}
 
+	// Refuse to rewrite zip files that Office wouldn't open anyway
+	if err := ValidatePackage(reader); err != nil {
+		return nil, err
+	}
+	mimeType, err := DocumentType(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	corePath := CorePartPath(reader)
+	dc, office, extra, err := readDublinCore(reader, corePath, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
 	docx := &DOCX{
-		FilePath:   filePath,
-		DublinCore: dublincore.New(),
+		DublinCore: dc,
+		Office:     office,
+		mimeType:   mimeType,
+		App:        readAppProperties(reader),
 		FileData:   fileData,
+		coreExtra:  extra,
+		corePath:   corePath,
+		duplicates: ziputil.DuplicateNames(reader),
+		parts:      ReadParts(reader),
+		openedDate: append([]string{}, dc.Date...),
 	}
+	docx.custom, docx.customPath = readCustomProperties(reader)
+	docx.opened = snapshot(docx)
+	logDuplicates(docx.duplicates)
 
-	// Try to read existing Dublin Core metadata
-	if coreFile, err := findFile(reader, corePropertiesPath); err == nil {
-		coreData, err := readZipFile(coreFile)
-		if err == nil {
-			if dc, err := extractDublinCore(coreData); err == nil {
-				docx.DublinCore = dc
+	return docx, nil
+}
+
+// OpenReadOnly reads the metadata of a DOCX file without keeping the file
+// content in memory. The returned document cannot be saved.
+func OpenReadOnly(filePath string) (*DOCX, error) {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zip reader: %w", err)
+	}
+	defer reader.Close()
+
+	mimeType, err := DocumentType(&reader.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	dc, office, _, err := readDublinCore(&reader.Reader, CorePartPath(&reader.Reader), mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	docx := &DOCX{
+		FilePath:   filePath,
+		DublinCore: dc,
+		Office:     office,
+		mimeType:   mimeType,
+		App:        readAppProperties(&reader.Reader),
+		duplicates: ziputil.DuplicateNames(&reader.Reader),
+		parts:      ReadParts(&reader.Reader),
+	}
+	docx.custom, docx.customPath = readCustomProperties(&reader.Reader)
+	logDuplicates(docx.duplicates)
+
+	return docx, nil
+}
+
+// ValidatePackage checks that reader contains the essential parts of a Word,
+// PowerPoint or Excel document
+func ValidatePackage(reader *zip.Reader) error {
+	if _, err := ziputil.FindFile(reader, contentTypesPath); err != nil {
+		return fmt.Errorf("%w: missing %s", ErrInvalidPackage, contentTypesPath)
+	}
+	_, err := DocumentType(reader)
+	return err
+}
+
+// readDublinCore reads the metadata, Office properties and unmodeled elements of the core.xml at corePath.
+// A missing part gives the defaults for mimeType; an unreadable one is a dublincore.ErrMalformedMetadata.
+func readDublinCore(reader *zip.Reader, corePath, mimeType string) (*dublincore.DublinCore, OfficeProperties, []rawxml.Element, error) {
+	coreFile, err := ziputil.FindFile(reader, corePath)
+	if err != nil {
+		logger().Info("no core properties part, using default metadata", "part", corePath)
+		return newDublinCore(mimeType), OfficeProperties{}, nil, nil
+	}
+
+	coreData, err := ziputil.ReadFile(coreFile)
+	if err != nil {
+		return nil, OfficeProperties{}, nil, fmt.Errorf("%w: failed to read %s: %v", dublincore.ErrMalformedMetadata, corePath, err)
+	}
+
+	dc, office, extra, err := parseCoreXML(coreData, mimeType)
+	if err != nil {
+		return nil, OfficeProperties{}, nil, fmt.Errorf("%w: %s: %v", dublincore.ErrMalformedMetadata, corePath, err)
+	}
+
+	return dc, office, extra, nil
+}
+
+// isModeledCoreElement reports whether writeCoreProperties regenerates the element
+func isModeledCoreElement(name xml.Name) bool {
+	switch name.Space {
+	case dcElementsNamespace:
+		for _, field := range dublincore.FieldNames() {
+			if name.Local == field && field != "keywords" && field != "category" {
+				return true
 			}
 		}
+		return false
+	case corePropertiesNamespace:
+		return name.Local == "keywords" || name.Local == "category" || isOfficeProperty(name.Local)
 	}
+	return false
+}
 
-	return docx, nil
+// readAppProperties reads docProps/app.xml, returning nil if it is missing or unreadable
+func readAppProperties(reader *zip.Reader) *AppProperties {
+	appFile, err := ziputil.FindFile(reader, appPropertiesPath)
+	if err != nil {
+		return nil
+	}
+	appData, err := ziputil.ReadFile(appFile)
+	if err != nil {
+		logger().Warn("ignoring unreadable app properties", "part", appFile.Name, "error", err)
+		return nil
+	}
+	app, err := parseAppXML(appData)
+	if err != nil {
+		logger().Warn("ignoring malformed app properties", "part", appFile.Name, "error", err)
+		return nil
+	}
+	return app
+}
+
+// GetMetadata returns the document's Dublin Core metadata
+func (d *DOCX) GetMetadata() *dublincore.DublinCore {
+	return d.DublinCore
+}
+
+// logDuplicates warns about entry names the archive repeats
+func logDuplicates(names []string) {
+	if len(names) > 0 {
+		logger().Warn("archive has duplicate entries, only the first of each is used", "entries", names)
+	}
+}
+
+// ListParts returns the zip entries of the document as it was opened: name,
+// compressed and uncompressed size and compression method
+func (d *DOCX) ListParts() []PartInfo {
+	return append([]PartInfo{}, d.parts...)
+}
+
+// DuplicateParts returns the entry names the archive holds more than once.
+// Such packages are malformed; Save keeps only the first entry of each name.
+func (d *DOCX) DuplicateParts() []string {
+	return append([]string{}, d.duplicates...)
+}
+
+// MimeType returns the MIME type of the document: Word, PowerPoint or Excel
+func (d *DOCX) MimeType() string {
+	return d.mimeType
+}
+
+// SetMetadata replaces the document's Dublin Core metadata
+func (d *DOCX) SetMetadata(dc *dublincore.DublinCore) {
+	d.DublinCore = dc
 }
 
 // Save saves the DOCX file with updated metadata
@@ -258,73 +691,271 @@ func (d *DOCX) Save(outputPath string) error {
 	if outputPath == "" {
 		outputPath = d.FilePath
 	}
+	if outputPath == "" {
+		return fmt.Errorf("no output path: document was opened from memory")
+	}
 
-	// Create a zip reader from the original file data
-	reader, err := zip.NewReader(bytes.NewReader(d.FileData), int64(len(d.FileData)))
+	// Check before creating the output so a failed save doesn't truncate it
+	if d.FileData == nil {
+		return errReadOnly
+	}
+
+	// Write next to the output and rename, so a failed save never leaves a truncated file
+	return fileutil.WriteAtomic(outputPath, d.SaveTo)
+}
+
+// SaveIfChanged saves like Save, but only when Changed reports edited metadata. An unchanged document isn't rewritten in place;
+// saved to another path, it is written with its original bytes. It reports
+// whether the metadata was rewritten.
+func (d *DOCX) SaveIfChanged(outputPath string) (bool, error) {
+	changed, err := d.Changed()
 	if err != nil {
-		return fmt.Errorf("failed to create zip reader from memory: %w", err)
+		return false, err
+	}
+	if changed {
+		return true, d.Save(outputPath)
 	}
 
-	// Create output file
-	outFile, err := os.Create(outputPath)
+	if outputPath == "" || outputPath == d.FilePath {
+		return false, nil
+	}
+	err = fileutil.WriteAtomic(outputPath, func(w io.Writer) error {
+		_, err := w.Write(d.FileData)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return false, fmt.Errorf("failed to write output file: %w", err)
 	}
-	defer outFile.Close()
+	return false, nil
+}
 
-	zipWriter := zip.NewWriter(outFile)
-	defer zipWriter.Close()
+// Changed reports whether the metadata differs from the document as opened:
+// the Dublin Core fields, the Office, app.xml and custom properties. The parsed
+// values are compared rather than the regenerated bytes, since core.xml as
+// another producer wrote it is rarely byte-identical to ours.
+func (d *DOCX) Changed() (bool, error) {
+	if d.FileData == nil {
+		return false, errReadOnly
+	}
+	return d.opened.changed(d), nil
+}
 
-	// Copy all files, replacing core.xml with updated metadata
-	for _, file := range reader.File {
-		if file.Name == corePropertiesPath {
-			// Create new core.xml with updated metadata
-			if err := d.writeCoreProperties(zipWriter); err != nil {
-				return fmt.Errorf("failed to write core properties: %w", err)
-			}
-			continue
-		}
+// properties is the editable state of a document, kept to tell whether it
+// was changed since it was opened or passed to a ProcessDir callback
+type properties struct {
+	dc     *dublincore.DublinCore
+	office OfficeProperties
+	app    *AppProperties // modeled fields only
+	custom []CustomProperty
+}
 
-		if err := copyZipFile(zipWriter, file); err != nil {
-			return fmt.Errorf("failed to copy file %s: %w", file.Name, err)
-		}
+// snapshot copies the editable state of d
+func snapshot(d *DOCX) properties {
+	p := properties{
+		dc:     d.DublinCore.Clone(),
+		office: d.Office,
+		custom: d.CustomProperties(),
 	}
+	if d.App != nil {
+		p.app = &AppProperties{Application: d.App.Application, Company: d.App.Company, Manager: d.App.Manager}
+	}
+	return p
+}
 
-	return nil
+// changed reports whether d differs from the snapshot
+func (p properties) changed(d *DOCX) bool {
+	if !p.dc.Equal(d.DublinCore) || p.dc.FormatSet() != d.DublinCore.FormatSet() {
+		return true
+	}
+	if p.office != d.Office || !slices.Equal(p.custom, d.CustomProperties()) {
+		return true
+	}
+	if p.app == nil || d.App == nil {
+		return p.app != nil || d.App != nil
+	}
+	return p.app.Application != d.App.Application || p.app.Company != d.App.Company || p.app.Manager != d.App.Manager
 }
 
-// Helper functions
-func findFile(reader *zip.Reader, name string) (*zip.File, error) {
-	for _, file := range reader.File {
-		if file.Name == name {
-			return file, nil
+// newPart is a part Save adds to the package after all the existing entries
+type newPart struct {
+	name  string
+	write func(zipWriter *zip.Writer) error
+
+	// contentType is registered in [Content_Types].xml; relType, when set,
+	// is the package relationship added for the part in _rels/.rels
+	contentType string
+	relType     string
+}
+
+// newParts returns the parts missing from reader that Save must create, sorted by name
+func (d *DOCX) newParts(reader *zip.Reader) []newPart {
+	var parts []newPart
+	if _, err := ziputil.FindFile(reader, d.corePath); err != nil {
+		// A relationship may already point at the missing part
+		relType := coreRelationshipType
+		if relationshipTarget(reader, coreRelationshipSuffix) != "" {
+			relType = ""
 		}
+		parts = append(parts, newPart{d.corePath, func(zipWriter *zip.Writer) error {
+			return d.writeCoreProperties(zipWriter, nil)
+		}, corePropertiesContentType, relType})
+	}
+	if d.customChanged && d.customPath == "" && len(d.custom) > 0 {
+		parts = append(parts, newPart{customPropertiesPath, func(zipWriter *zip.Writer) error {
+			return d.writeCustomProperties(zipWriter, nil)
+		}, customPropertiesContentType, customRelationshipType})
 	}
-	return nil, fmt.Errorf("file not found: %s", name)
+	if _, err := ziputil.FindFile(reader, packageRelsPath); err != nil && slices.ContainsFunc(parts, newPart.related) {
+		created := slices.Clone(parts)
+		parts = append(parts, newPart{name: packageRelsPath, write: func(zipWriter *zip.Writer) error {
+			return d.writePackageRels(zipWriter, nil, created)
+		}})
+	}
+
+	slices.SortFunc(parts, func(a, b newPart) int { return strings.Compare(a.name, b.name) })
+	return parts
+}
+
+// related reports whether the part needs a package relationship
+func (p newPart) related() bool {
+	return p.relType != ""
 }
 
-func readZipFile(file *zip.File) ([]byte, error) {
-	rc, err := file.Open()
+// registerParts copies [Content_Types].xml with an Override for each created part
+func (d *DOCX) registerParts(zipWriter *zip.Writer, src *zip.File, parts []newPart) error {
+	data, err := ziputil.ReadFile(src)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	for _, part := range parts {
+		if part.contentType == "" {
+			continue
+		}
+		if data, err = addContentTypeOverride(data, "/"+part.name, part.contentType); err != nil {
+			return fmt.Errorf("%s: %w", src.Name, err)
+		}
 	}
-	defer rc.Close()
 
-	return io.ReadAll(rc)
+	writer, err := ziputil.CreateRegenerated(zipWriter, src, d.Deterministic)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
 }
 
-func copyZipFile(dest *zip.Writer, src *zip.File) error {
-	srcReader, err := src.Open()
+// writePackageRels writes _rels/.rels with a relationship for each created
+// part that needs one, replacing src or as a new entry when src is nil
+func (d *DOCX) writePackageRels(zipWriter *zip.Writer, src *zip.File, parts []newPart) error {
+	data := []byte(emptyPackageRels)
+	if src != nil {
+		var err error
+		if data, err = ziputil.ReadFile(src); err != nil {
+			return err
+		}
+	}
+	for _, part := range parts {
+		if !part.related() {
+			continue
+		}
+		var err error
+		if data, err = addPackageRelationship(data, part.relType, part.name); err != nil {
+			return fmt.Errorf("%s: %w", packageRelsPath, err)
+		}
+	}
+
+	var writer io.Writer
+	var err error
+	if src != nil {
+		writer, err = ziputil.CreateRegenerated(zipWriter, src, d.Deterministic)
+	} else {
+		writer, err = ziputil.CreateNew(zipWriter, packageRelsPath, d.Deterministic)
+	}
 	if err != nil {
 		return err
 	}
-	defer srcReader.Close()
+	_, err = writer.Write(data)
+	return err
+}
+
+// SaveTo writes the DOCX with updated metadata to w, e.g. a bytes.Buffer or an HTTP response.
+//
+// Entry order is stable: every entry of the original package is written in its
+// original position, regenerated parts included, and parts the package didn't
+// have (such as a new docProps/custom.xml, or docProps/core.xml when the
+// package had no core properties) follow in name order.
+func (d *DOCX) SaveTo(w io.Writer) error {
+	if d.FileData == nil {
+		return errReadOnly
+	}
 
-	destWriter, err := dest.Create(src.Name)
+	// Create a zip reader from the original file data
+	reader, err := zip.NewReader(bytes.NewReader(d.FileData), int64(len(d.FileData)))
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to create zip reader from memory: %w", err)
 	}
 
-	_, err = io.Copy(destWriter, srcReader)
-	return err
+	zipWriter := zip.NewWriter(w)
+
+	// New parts must be registered in the content types and package relationships
+	added := d.newParts(reader)
+	relate := slices.ContainsFunc(added, newPart.related)
+
+	// Copy all files, replacing core.xml with updated metadata. Only the first
+	// entry of each name is written, so a malformed input with duplicates
+	// still produces a valid package.
+	written := map[string]bool{}
+	for _, file := range reader.File {
+		if written[strings.ToLower(file.Name)] {
+			continue
+		}
+		written[strings.ToLower(file.Name)] = true
+
+		if len(added) > 0 && file.Name == contentTypesPath {
+			if err := d.registerParts(zipWriter, file, added); err != nil {
+				return fmt.Errorf("failed to register new parts: %w", err)
+			}
+			continue
+		}
+		if relate && strings.EqualFold(file.Name, packageRelsPath) {
+			if err := d.writePackageRels(zipWriter, file, added); err != nil {
+				return fmt.Errorf("failed to register new parts: %w", err)
+			}
+			continue
+		}
+		if d.customChanged && strings.EqualFold(file.Name, d.customPath) {
+			if err := d.writeCustomProperties(zipWriter, file); err != nil {
+				return fmt.Errorf("failed to write custom properties: %w", err)
+			}
+			continue
+		}
+		if strings.EqualFold(file.Name, d.corePath) {
+			// Create new core.xml with updated metadata
+			if err := d.writeCoreProperties(zipWriter, file); err != nil {
+				return fmt.Errorf("failed to write core properties: %w", err)
+			}
+			continue
+		}
+		if strings.EqualFold(file.Name, appPropertiesPath) && d.App != nil {
+			if err := d.writeAppProperties(zipWriter, file); err != nil {
+				return fmt.Errorf("failed to write app properties: %w", err)
+			}
+			continue
+		}
+
+		if err := ziputil.CopyFile(zipWriter, file, d.Deterministic); err != nil {
+			return fmt.Errorf("failed to copy file %s: %w", file.Name, err)
+		}
+	}
+
+	for _, part := range added {
+		if err := part.write(zipWriter); err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finish zip archive: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,1134 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+)
+
+// buildPackage zips the files under testdata/<name> into an Office package,
+// with the parts in overrides replacing or adding to them. A nil override
+// leaves the part out.
+func buildPackage(tb testing.TB, name string, overrides map[string][]byte) []byte {
+	tb.Helper()
+
+	parts := map[string][]byte{}
+	root := filepath.Join("testdata", name)
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		parts[filepath.ToSlash(rel)] = data
+		return err
+	})
+	if err != nil {
+		tb.Fatalf("failed to read %s: %v", root, err)
+	}
+	for part, data := range overrides {
+		parts[part] = data
+	}
+
+	// [Content_Types].xml goes first, as Office writes it
+	names := []string{contentTypesPath}
+	for part, data := range parts {
+		if data != nil && part != contentTypesPath {
+			names = append(names, part)
+		}
+	}
+	slices.Sort(names[1:])
+
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for _, part := range names {
+		w, err := zipWriter.Create(part)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		if _, err := w.Write(parts[part]); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// readPart returns the content of the entry name of a package
+func readPart(tb testing.TB, data []byte, name string) string {
+	tb.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	file, err := ziputil.FindFile(reader, name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	content, err := ziputil.ReadFile(file)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return string(content)
+}
+
+// withKeywords returns the Word fixture's core.xml with the cp:keywords value
+// replaced by keywords
+func withKeywords(tb testing.TB, keywords string) []byte {
+	tb.Helper()
+
+	core, err := os.ReadFile(filepath.Join("testdata", "word", "docProps", "core.xml"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return bytes.Replace(core, []byte("<cp:keywords>Go, PHP, AWS</cp:keywords>"),
+		[]byte("<cp:keywords>"+keywords+"</cp:keywords>"), 1)
+}
+
+func TestKeywordsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		keywords string
+	}{
+		{"comma", "Go, PHP, AWS"},
+		{"semicolon", "Go; PHP; AWS"},
+		{"semicolon without spaces", "Go;PHP;AWS"},
+		{"mixed", "Go, PHP; AWS"},
+		{"empty entries", "Go,, PHP;; AWS,"},
+	}
+	want := []string{"Go", "PHP", "AWS"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "resume.docx")
+			data := buildPackage(t, "word", map[string][]byte{corePropertiesPath: withKeywords(t, tt.keywords)})
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			if !slices.Equal(doc.DublinCore.Keywords, want) {
+				t.Fatalf("Keywords = %q, want %q", doc.DublinCore.Keywords, want)
+			}
+
+			doc.DublinCore.Title = []string{"Analista Go"}
+			if err := doc.Save(""); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			saved, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			core := readPart(t, saved, corePropertiesPath)
+			if !strings.Contains(core, "<cp:keywords>Go, PHP, AWS</cp:keywords>") {
+				t.Errorf("core.xml doesn't hold the comma-joined keywords:\n%s", core)
+			}
+			if strings.Contains(core, "dcterms:keyword") {
+				t.Errorf("core.xml has dcterms:keyword elements:\n%s", core)
+			}
+
+			reopened, err := OpenBytes(saved)
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			if !slices.Equal(reopened.DublinCore.Keywords, want) {
+				t.Errorf("Keywords after save = %q, want %q", reopened.DublinCore.Keywords, want)
+			}
+		})
+	}
+}
+
+func TestCorePropertiesOmitsLang(t *testing.T) {
+	doc, err := OpenBytes(buildPackage(t, "word", nil))
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	doc.DublinCore.SetLangTitle("en", "Backend Analyst")
+	doc.DublinCore.SetLangDescription("en", "Backend developer")
+
+	var buf bytes.Buffer
+	if err := doc.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+	core := readPart(t, buf.Bytes(), corePropertiesPath)
+	if strings.Contains(core, "xml:lang") || strings.Contains(core, "Backend Analyst") {
+		t.Errorf("core.xml holds language-tagged values:\n%s", core)
+	}
+	if !strings.Contains(core, "<dc:title>Analista Backend</dc:title>") {
+		t.Errorf("core.xml lost the default title:\n%s", core)
+	}
+}
+
+func TestParseCoreXMLIgnoresLang(t *testing.T) {
+	core := `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		`<dc:title xml:lang="pt-BR">Analista</dc:title><dc:description xml:lang="en">Developer</dc:description></cp:coreProperties>`
+
+	dc, _, _, err := parseCoreXML([]byte(core), MimeTypeDOCX)
+	if err != nil {
+		t.Fatalf("parseCoreXML: %v", err)
+	}
+	if !slices.Equal(dc.Title, []string{"Analista"}) || !slices.Equal(dc.Description, []string{"Developer"}) {
+		t.Errorf("Title = %q, Description = %q, want the tagged values as default ones", dc.Title, dc.Description)
+	}
+	if len(dc.TitleLang) > 0 || len(dc.DescriptionLang) > 0 {
+		t.Errorf("TitleLang = %v, DescriptionLang = %v, want none", dc.TitleLang, dc.DescriptionLang)
+	}
+}
+
+func TestSavePreservesUnmodeledCoreElements(t *testing.T) {
+	doc, err := OpenBytes(buildPackage(t, "word", nil))
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	doc.DublinCore.SetTitle("Analista Go")
+
+	var buf bytes.Buffer
+	if err := doc.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+	core := readPart(t, buf.Bytes(), corePropertiesPath)
+	for _, want := range []string{
+		"<dc:title>Analista Go</dc:title>",
+		"<cp:lastModifiedBy>Eduardo</cp:lastModifiedBy>",
+		"<cp:revision>7</cp:revision>",
+		"<cp:contentStatus>Draft</cp:contentStatus>",
+		"<cp:lastPrinted>2024-02-01T09:00:00Z</cp:lastPrinted>",
+		`<dcterms:created xsi:type="dcterms:W3CDTF">2024-01-02T10:00:00Z</dcterms:created>`,
+	} {
+		if !strings.Contains(core, want) {
+			t.Errorf("core.xml is missing %s:\n%s", want, core)
+		}
+	}
+
+	reopened, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	if reopened.Office != doc.Office {
+		t.Errorf("Office = %+v after save, want %+v", reopened.Office, doc.Office)
+	}
+}
+
+func TestParseCoreXMLFixtures(t *testing.T) {
+	tests := []struct {
+		file     string
+		wantErr  string
+		title    []string
+		creator  []string
+		keywords []string
+		revision string
+		extra    int // unmodeled elements kept for Save
+	}{
+		{file: "prefixed.xml", title: []string{"Analista Backend"}, creator: []string{"Eduardo Moro"}, keywords: []string{"Go", "PHP"}, revision: "3", extra: 1},
+		{file: "default-namespace.xml", title: []string{"Analista Backend"}, creator: []string{"Eduardo Moro"}, keywords: []string{"Go", "PHP"}, revision: "3"},
+		{file: "mixed.xml", title: []string{"Analista Backend"}, creator: []string{"Eduardo Moro"}, keywords: []string{"Go", "PHP"}, revision: "3", extra: 1},
+		{file: "missing-root.xml", wantErr: "no root element"},
+		{file: "wrong-root.xml", wantErr: "not cp:coreProperties"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "core", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dc, office, extra, err := parseCoreXML(data, MimeTypeDOCX)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCoreXML: %v", err)
+			}
+
+			if !slices.Equal(dc.Title, tt.title) {
+				t.Errorf("Title = %q, want %q", dc.Title, tt.title)
+			}
+			if !slices.Equal(dc.Creator, tt.creator) {
+				t.Errorf("Creator = %q, want %q", dc.Creator, tt.creator)
+			}
+			if !slices.Equal(dc.Keywords, tt.keywords) {
+				t.Errorf("Keywords = %q, want %q", dc.Keywords, tt.keywords)
+			}
+			if office.Revision != tt.revision {
+				t.Errorf("Revision = %q, want %q", office.Revision, tt.revision)
+			}
+			if len(extra) != tt.extra {
+				t.Errorf("kept %d unmodeled elements, want %d", len(extra), tt.extra)
+			}
+		})
+	}
+}
+
+func TestCorePartPath(t *testing.T) {
+	rels := func(target string) []byte {
+		return []byte(`<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="` + target + `"/></Relationships>`)
+	}
+	core, err := os.ReadFile(filepath.Join("testdata", "word", "docProps", "core.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[string][]byte
+		want      string
+	}{
+		{"default", nil, "docProps/core.xml"},
+		{"relationship target", map[string][]byte{
+			"_rels/.rels": rels("/meta/props.xml"), "meta/props.xml": core, corePropertiesPath: nil,
+		}, "meta/props.xml"},
+		{"different case", map[string][]byte{
+			"DocProps/Core.XML": core, corePropertiesPath: nil,
+		}, "DocProps/Core.XML"},
+		{"dangling relationship", map[string][]byte{"_rels/.rels": rels("missing.xml")}, "docProps/core.xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildPackage(t, "word", tt.overrides)
+			reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := CorePartPath(reader); got != tt.want {
+				t.Errorf("CorePartPath = %q, want %q", got, tt.want)
+			}
+
+			doc, err := OpenBytes(data)
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			if !slices.Equal(doc.DublinCore.Title, []string{"Analista Backend"}) {
+				t.Errorf("Title = %q, want the fixture's", doc.DublinCore.Title)
+			}
+		})
+	}
+}
+
+func TestRoundTripWithoutEdits(t *testing.T) {
+	for _, layout := range []struct {
+		name string
+		opts func(*DOCX)
+	}{
+		{"default", func(*DOCX) {}},
+		{"word layout", func(d *DOCX) { d.Marshal = &WordMarshalOptions }},
+	} {
+		t.Run(layout.name, func(t *testing.T) {
+			doc, err := OpenBytes(buildPackage(t, "word", nil))
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			layout.opts(doc)
+
+			var buf bytes.Buffer
+			if err := doc.SaveTo(&buf); err != nil {
+				t.Fatalf("SaveTo: %v", err)
+			}
+			core := readPart(t, buf.Bytes(), corePropertiesPath)
+			if !strings.Contains(core, "<dc:description>Backend developer</dc:description>") {
+				t.Errorf("description isn't written as dc:description:\n%s", core)
+			}
+
+			reopened, err := OpenBytes(buf.Bytes())
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			if !reopened.DublinCore.Equal(doc.DublinCore) {
+				t.Errorf("metadata changed on save: %+v", dublincore.Diff(doc.DublinCore, reopened.DublinCore))
+			}
+		})
+	}
+}
+
+func TestFormatWrittenOnlyWhenExplicit(t *testing.T) {
+	withFormat := bytes.Replace(withKeywords(t, "Go"), []byte("</cp:coreProperties>"),
+		[]byte("<dc:format>application/msword</dc:format></cp:coreProperties>"), 1)
+
+	tests := []struct {
+		name  string
+		core  []byte
+		edit  func(d *DOCX)
+		want  string // dc:format element expected in core.xml, "" for none
+		shown string // Format after opening
+	}{
+		{"defaulted", nil, func(*DOCX) {}, "", MimeTypeDOCX},
+		{"other edit", nil, func(d *DOCX) { d.DublinCore.SetTitle("Analista Go") }, "", MimeTypeDOCX},
+		{"in original", withFormat, func(*DOCX) {}, "<dc:format>application/msword</dc:format>", "application/msword"},
+		{"SetFormat", nil, func(d *DOCX) { d.DublinCore.SetFormat("application/pdf") }, "<dc:format>application/pdf</dc:format>", MimeTypeDOCX},
+		{"SetFormat with the document type", nil, func(d *DOCX) { d.DublinCore.SetFormat(MimeTypeDOCX) }, "<dc:format>" + MimeTypeDOCX + "</dc:format>", MimeTypeDOCX},
+		{"Set", nil, func(d *DOCX) { d.DublinCore.Set("format", []string{"text/plain"}) }, "<dc:format>text/plain</dc:format>", MimeTypeDOCX},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var overrides map[string][]byte
+			if tt.core != nil {
+				overrides = map[string][]byte{corePropertiesPath: tt.core}
+			}
+			doc, err := OpenBytes(buildPackage(t, "word", overrides))
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			if !slices.Equal(doc.DublinCore.Format, []string{tt.shown}) {
+				t.Errorf("Format = %q after opening, want %q", doc.DublinCore.Format, tt.shown)
+			}
+			tt.edit(doc)
+
+			var buf bytes.Buffer
+			if err := doc.SaveTo(&buf); err != nil {
+				t.Fatalf("SaveTo: %v", err)
+			}
+			core := readPart(t, buf.Bytes(), corePropertiesPath)
+			if tt.want == "" && strings.Contains(core, "dc:format") {
+				t.Errorf("core.xml has a dc:format that wasn't set:\n%s", core)
+			}
+			if tt.want != "" && !strings.Contains(core, tt.want) {
+				t.Errorf("core.xml is missing %s:\n%s", tt.want, core)
+			}
+		})
+	}
+}
+
+func FuzzParseCoreXML(f *testing.F) {
+	seeds, err := filepath.Glob(filepath.Join("testdata", "core", "*.xml"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	seeds = append(seeds, filepath.Join("testdata", "word", "docProps", "core.xml"))
+	for _, path := range seeds {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+		f.Add(data[:len(data)/2]) // truncated
+	}
+	f.Add([]byte(""))
+	f.Add([]byte(`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties"><dc:title>`))
+	f.Add([]byte(`<coreProperties><title>unbound</title></coreProperties>`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		dc, office, extra, err := parseCoreXML(data, MimeTypeDOCX)
+		if err != nil {
+			return
+		}
+
+		// Whatever parses must be written back as a core.xml that parses again
+		doc := &DOCX{DublinCore: dc, Office: office, coreExtra: extra, mimeType: MimeTypeDOCX}
+		out, err := doc.CoreProperties().ToXML()
+		if err != nil {
+			t.Fatalf("parsed core.xml can't be written: %v", err)
+		}
+		if _, _, _, err := parseCoreXML(out, MimeTypeDOCX); err != nil {
+			t.Fatalf("written core.xml doesn't parse: %v\n%s", err, out)
+		}
+	})
+}
+
+func FuzzOpenBytes(f *testing.F) {
+	f.Add(buildPackage(f, "word", nil))
+	core, err := os.ReadFile(filepath.Join("testdata", "core", "mixed.xml"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(buildPackage(f, "word", map[string][]byte{corePropertiesPath: core}))
+	f.Add(buildPackage(f, "word", map[string][]byte{corePropertiesPath: nil}))
+	f.Add(buildPackage(f, "word", map[string][]byte{corePropertiesPath: []byte("<cp:coreProperties")}))
+	f.Add([]byte("PK\x03\x04"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		doc, err := OpenBytes(data)
+		if err != nil {
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := doc.SaveTo(&buf); err != nil {
+			// A package that opens may still hold entries that can't be copied
+			return
+		}
+		reopened, err := OpenBytes(buf.Bytes())
+		if err != nil {
+			t.Fatalf("saved package doesn't open: %v", err)
+		}
+		if !reopened.DublinCore.Equal(doc.DublinCore.Sanitized()) {
+			t.Errorf("metadata changed on save: %+v", dublincore.Diff(doc.DublinCore.Sanitized(), reopened.DublinCore))
+		}
+	})
+}
+
+// entryNames returns the names of the entries of a package in archive order
+func entryNames(tb testing.TB, data []byte) []string {
+	tb.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	names := make([]string, len(reader.File))
+	for i, file := range reader.File {
+		names[i] = file.Name
+	}
+	return names
+}
+
+func TestSaveEntryOrder(t *testing.T) {
+	custom := []byte(`<?xml version="1.0" encoding="UTF-8"?><Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/custom-properties" xmlns:vt="http://schemas.openxmlformats.org/officeDocument/2006/docPropsVTypes"/>`)
+
+	tests := []struct {
+		name      string
+		overrides map[string][]byte
+		edit      func(t *testing.T, d *DOCX)
+		added     []string // entries expected after the original ones
+		custom    bool     // whether the saved package has the Vaga property
+	}{
+		{"metadata edit", nil, func(t *testing.T, d *DOCX) { d.DublinCore.SetTitle("Analista Go") }, nil, false},
+		{"new custom part", nil, func(t *testing.T, d *DOCX) {
+			if err := d.SetCustomProperty("Vaga", "Backend"); err != nil {
+				t.Fatal(err)
+			}
+		}, []string{customPropertiesPath}, true},
+		{"existing custom part", map[string][]byte{customPropertiesPath: custom}, func(t *testing.T, d *DOCX) {
+			if err := d.SetCustomProperty("Vaga", "Backend"); err != nil {
+				t.Fatal(err)
+			}
+		}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildPackage(t, "word", tt.overrides)
+			doc, err := OpenBytes(data)
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			tt.edit(t, doc)
+
+			var buf bytes.Buffer
+			if err := doc.SaveTo(&buf); err != nil {
+				t.Fatalf("SaveTo: %v", err)
+			}
+			want := append(entryNames(t, data), tt.added...)
+			if got := entryNames(t, buf.Bytes()); !slices.Equal(got, want) {
+				t.Errorf("entries = %q, want %q", got, want)
+			}
+
+			reopened, err := OpenBytes(buf.Bytes())
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			if _, ok := reopened.CustomProperty("Vaga"); ok != tt.custom {
+				t.Errorf("custom property found = %v after save, want %v", ok, tt.custom)
+			}
+		})
+	}
+}
+
+func TestProcessDir(t *testing.T) {
+	errSkip := errors.New("skip")
+
+	tests := []struct {
+		name    string
+		fn      func(d *DOCX) error
+		wantErr bool
+		saved   bool
+	}{
+		{"no edit", func(d *DOCX) error { return nil }, false, false},
+		{"same title", func(d *DOCX) error { d.DublinCore.SetTitle(d.DublinCore.Title[0]); return nil }, false, false},
+		{"edit reverted", func(d *DOCX) error {
+			title := d.DublinCore.Title[0]
+			d.DublinCore.SetTitle("Analista Go")
+			d.DublinCore.SetTitle(title)
+			return nil
+		}, false, false},
+		{"custom property added and deleted", func(d *DOCX) error {
+			if err := d.SetCustomProperty("Vaga", "Backend"); err != nil {
+				return err
+			}
+			d.DeleteCustomProperty("Vaga")
+			return nil
+		}, false, false},
+		{"title", func(d *DOCX) error { d.DublinCore.SetTitle("Analista Go"); return nil }, false, true},
+		{"replaced metadata", func(d *DOCX) error {
+			dc := d.DublinCore.Clone()
+			dc.SetLangTitle("en", "Go Analyst")
+			d.SetMetadata(dc)
+			return nil
+		}, false, true},
+		{"office property", func(d *DOCX) error { d.Office.ContentStatus = "Final"; return nil }, false, true},
+		{"app property", func(d *DOCX) error { d.App.Company = "Initech"; return nil }, false, true},
+		{"custom property", func(d *DOCX) error { return d.SetCustomProperty("Vaga", "Backend") }, false, true},
+		{"callback error", func(d *DOCX) error { d.DublinCore.SetTitle("Analista Go"); return errSkip }, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			data := buildPackage(t, "word", nil)
+			path := filepath.Join(dir, "cv.docx")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			summary := ProcessDir(dir, tt.fn)
+			if summary.Processed != 1 {
+				t.Errorf("Processed = %d, want 1", summary.Processed)
+			}
+			if err := summary.Errors[path]; (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error = %v", err, tt.wantErr)
+			}
+			if saved := summary.Saved == 1; saved != tt.saved {
+				t.Errorf("saved = %v, want %v", saved, tt.saved)
+			}
+
+			stored, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rewritten := !bytes.Equal(stored, data); rewritten != tt.saved {
+				t.Errorf("file rewritten = %v, want %v", rewritten, tt.saved)
+			}
+		})
+	}
+
+	t.Run("walk", func(t *testing.T) {
+		dir := t.TempDir()
+		data := buildPackage(t, "word", nil)
+		files := map[string][]byte{
+			"b.docx":      data,
+			"a.DOCX":      data,
+			"sub/c.xlsx":  data,
+			"notes.txt":   []byte("not a package"),
+			"broken.pptx": []byte("not a package"),
+		}
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var visited []string
+		summary := ProcessDir(dir, func(d *DOCX) error {
+			rel, _ := filepath.Rel(dir, d.FilePath)
+			visited = append(visited, filepath.ToSlash(rel))
+			return nil
+		})
+		if want := []string{"a.DOCX", "b.docx", "sub/c.xlsx"}; !slices.Equal(visited, want) {
+			t.Errorf("visited %q, want %q", visited, want)
+		}
+		if summary.Processed != 3 || summary.Saved != 0 || summary.Failed() != 1 {
+			t.Errorf("summary = %d processed, %d saved, %d failed, want 3, 0, 1", summary.Processed, summary.Saved, summary.Failed())
+		}
+		if _, ok := summary.Errors[filepath.Join(dir, "broken.pptx")]; !ok {
+			t.Errorf("errors = %v, want broken.pptx", summary.Errors)
+		}
+	})
+}
+
+func TestTypeAndCategoryRoundTrip(t *testing.T) {
+	doc, err := OpenBytes(buildPackage(t, "word", nil))
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	doc.DublinCore.Type = []string{"Text"}
+	doc.DublinCore.Category = []string{"curriculo"}
+
+	var buf bytes.Buffer
+	if err := doc.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+	core := readPart(t, buf.Bytes(), "docProps/core.xml")
+	for _, element := range []string{"<dc:type>Text</dc:type>", "<cp:category>curriculo</cp:category>"} {
+		if !strings.Contains(core, element) {
+			t.Errorf("core.xml = %s, want it to contain %s", core, element)
+		}
+	}
+
+	reopened, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	if got := reopened.DublinCore; !slices.Equal(got.Type, []string{"Text"}) || !slices.Equal(got.Category, []string{"curriculo"}) {
+		t.Errorf("reopened Type %q and Category %q, want [Text] and [curriculo]", got.Type, got.Category)
+	}
+}
+
+// appendEntry returns the package data with another entry called name added
+// at the end, even if the archive already has one
+func appendEntry(tb testing.TB, data []byte, name, content string) []byte {
+	tb.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	for _, file := range reader.File {
+		if err := zipWriter.Copy(file); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	w, err := zipWriter.Create(name)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		tb.Fatal(err)
+	}
+	if err := zipWriter.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDuplicateCoreParts(t *testing.T) {
+	second := `<?xml version="1.0" encoding="UTF-8"?><cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Second</dc:title></cp:coreProperties>`
+
+	for _, name := range []string{"docProps/core.xml", "DocProps/Core.xml"} {
+		t.Run(name, func(t *testing.T) {
+			data := appendEntry(t, buildPackage(t, "word", nil), name, second)
+			doc, err := OpenBytes(data)
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			if got := doc.DuplicateParts(); !slices.Equal(got, []string{"docProps/core.xml"}) {
+				t.Errorf("DuplicateParts = %q, want [docProps/core.xml]", got)
+			}
+			if got := doc.DublinCore.Title; !slices.Equal(got, []string{"Analista Backend"}) {
+				t.Errorf("Title = %q, want the first core.xml's", got)
+			}
+
+			doc.DublinCore.SetTitle("Analista Go")
+			var buf bytes.Buffer
+			if err := doc.SaveTo(&buf); err != nil {
+				t.Fatalf("SaveTo: %v", err)
+			}
+			var cores int
+			for _, entry := range entryNames(t, buf.Bytes()) {
+				if strings.EqualFold(entry, "docProps/core.xml") {
+					cores++
+				}
+			}
+			if cores != 1 {
+				t.Errorf("saved package has %d core.xml entries, want 1", cores)
+			}
+
+			reopened, err := OpenBytes(buf.Bytes())
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			if len(reopened.DuplicateParts()) > 0 {
+				t.Errorf("saved package still has duplicates %q", reopened.DuplicateParts())
+			}
+			if got := reopened.DublinCore.Title; !slices.Equal(got, []string{"Analista Go"}) {
+				t.Errorf("reopened Title = %q, want [Analista Go]", got)
+			}
+		})
+	}
+}
+
+// representativeDOCX writes a CV-sized Word document to a temporary file: the
+// Word fixture with a body of a few hundred paragraphs and a 1 MB photo that
+// doesn't compress, and returns its path
+func representativeDOCX(b *testing.B) string {
+	b.Helper()
+
+	var body strings.Builder
+	body.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`)
+	for i := range 500 {
+		fmt.Fprintf(&body, `<w:p><w:pPr><w:pStyle w:val="ListParagraph"/></w:pPr><w:r><w:rPr><w:b/></w:rPr><w:t>Experiência %d</w:t></w:r><w:r><w:t xml:space="preserve"> Desenvolvimento de APIs em Go, filas com SQS e infraestrutura na AWS.</w:t></w:r></w:p>`, i)
+	}
+	body.WriteString(`</w:body></w:document>`)
+
+	photo := make([]byte, 1<<20)
+	rand.NewChaCha8([32]byte{}).Read(photo)
+
+	data := buildPackage(b, "word", map[string][]byte{
+		"word/document.xml":     []byte(body.String()),
+		"word/media/image1.png": photo,
+	})
+	path := filepath.Join(b.TempDir(), "cv.docx")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+func BenchmarkOpenSave(b *testing.B) {
+	path := representativeDOCX(b)
+	output := filepath.Join(b.TempDir(), "out.docx")
+
+	b.ResetTimer()
+	for i := range b.N {
+		doc, err := Open(path)
+		if err != nil {
+			b.Fatalf("Open: %v", err)
+		}
+		doc.DublinCore.SetTitle(fmt.Sprintf("Analista %d", i))
+		if err := doc.Save(output); err != nil {
+			b.Fatalf("Save: %v", err)
+		}
+	}
+}
+
+// BenchmarkOpen and BenchmarkOpenReadOnly compare reading the metadata with
+// and without keeping the whole file in memory
+func BenchmarkOpen(b *testing.B) {
+	path := representativeDOCX(b)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := Open(path); err != nil {
+			b.Fatalf("Open: %v", err)
+		}
+	}
+}
+
+func BenchmarkOpenReadOnly(b *testing.B) {
+	path := representativeDOCX(b)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := OpenReadOnly(path); err != nil {
+			b.Fatalf("OpenReadOnly: %v", err)
+		}
+	}
+}
+
+// coreElementPattern matches the start tag of a prefixed core.xml property
+var coreElementPattern = regexp.MustCompile(`<((?:dc|cp|dcterms):[A-Za-z]+)[ />]`)
+
+// elementOrder returns the prefixed names of the properties of a core.xml in document order
+func elementOrder(core string) []string {
+	var names []string
+	for _, match := range coreElementPattern.FindAllStringSubmatch(core, -1) {
+		if match[1] != "cp:coreProperties" {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+func TestWordElementOrder(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "word", "docProps", "core.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := elementOrder(string(fixture))
+
+	// The same elements in reverse, as another producer might order them
+	body := regexp.MustCompile(`(?s)(<cp:coreProperties[^>]*>)(.*)(</cp:coreProperties>)`).FindSubmatchIndex(fixture)
+	var elements []string
+	for _, name := range want {
+		elements = append(elements, regexp.MustCompile(`<`+name+`[ >].*?</`+name+`>`).FindString(string(fixture)))
+	}
+	slices.Reverse(elements)
+	reversed := string(fixture[:body[3]]) + strings.Join(elements, "") + string(fixture[body[6]:])
+
+	tests := []struct {
+		name string
+		core []byte
+	}{
+		{"word order", fixture},
+		{"reversed", []byte(reversed)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := OpenBytes(buildPackage(t, "word", map[string][]byte{"docProps/core.xml": tt.core}))
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			doc.Marshal = &WordMarshalOptions
+			doc.DublinCore.SetTitle("Analista Go")
+
+			var buf bytes.Buffer
+			if err := doc.SaveTo(&buf); err != nil {
+				t.Fatalf("SaveTo: %v", err)
+			}
+			if got := elementOrder(readPart(t, buf.Bytes(), "docProps/core.xml")); !slices.Equal(got, want) {
+				t.Errorf("element order = %q, want Word's %q", got, want)
+			}
+		})
+	}
+}
+
+func TestSaveSanitizesValues(t *testing.T) {
+	doc, err := OpenBytes(buildPackage(t, "word", nil))
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	doc.DublinCore.SetTitle("Analista\x00 Go\xff")
+	doc.DublinCore.Creator = []string{"Eduardo\x1b Moro"}
+	doc.DublinCore.Keywords = []string{"Go", "AWS\x7f"}
+
+	var buf bytes.Buffer
+	if err := doc.SaveTo(&buf); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+	core := readPart(t, buf.Bytes(), "docProps/core.xml")
+	if err := xml.Unmarshal([]byte(core), new(struct{})); err != nil {
+		t.Fatalf("saved core.xml isn't well-formed: %v\n%s", err, core)
+	}
+	if !strings.Contains(doc.DublinCore.Title[0], "\x00") {
+		t.Error("SaveTo sanitized the document's own metadata")
+	}
+
+	reopened, err := OpenBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("OpenBytes: %v", err)
+	}
+	for _, tt := range []struct {
+		field string
+		want  []string
+	}{
+		{"title", []string{"Analista Go\ufffd"}},
+		{"creator", []string{"Eduardo Moro"}},
+		{"keywords", []string{"Go", "AWS"}},
+	} {
+		if got, _ := reopened.DublinCore.Get(tt.field); !slices.Equal(got, tt.want) {
+			t.Errorf("reopened %s = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestSaveCreatesCoreProperties(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]byte
+	}{
+		{"relationship kept", map[string][]byte{corePropertiesPath: nil}},
+		{"no package relationships", map[string][]byte{corePropertiesPath: nil, packageRelsPath: nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := OpenBytes(buildPackage(t, "word", tt.overrides))
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			doc.DublinCore.SetTitle("Hello")
+
+			var buf bytes.Buffer
+			if err := doc.SaveTo(&buf); err != nil {
+				t.Fatalf("SaveTo: %v", err)
+			}
+			saved := buf.Bytes()
+			if got := strings.Count(readPart(t, saved, packageRelsPath), coreRelationshipType); got != 1 {
+				t.Errorf("_rels/.rels has %d core-properties relationships, want 1", got)
+			}
+			if got := strings.Count(readPart(t, saved, contentTypesPath), `PartName="/docProps/core.xml"`); got != 1 {
+				t.Errorf("[Content_Types].xml has %d overrides for core.xml, want 1", got)
+			}
+
+			reopened, err := OpenBytes(saved)
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			if got := reopened.DublinCore.Title; !slices.Equal(got, []string{"Hello"}) {
+				t.Errorf("reopened Title = %q, want [Hello]", got)
+			}
+		})
+	}
+}
+
+func TestChanged(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]byte
+		edit      func(d *DOCX)
+		want      bool
+	}{
+		{"unedited", nil, func(d *DOCX) {}, false},
+		{"word layout", nil, func(d *DOCX) { d.Marshal = &WordMarshalOptions }, false},
+		{"no core part", map[string][]byte{corePropertiesPath: nil}, func(d *DOCX) {}, false},
+		{"same title", nil, func(d *DOCX) { d.DublinCore.SetTitle("Analista Backend") }, false},
+		{"replaced by a clone", nil, func(d *DOCX) { d.SetMetadata(d.DublinCore.Clone()) }, false},
+		{"title", nil, func(d *DOCX) { d.DublinCore.SetTitle("Analista Go") }, true},
+		{"tagged title", nil, func(d *DOCX) { d.DublinCore.SetLangTitle("en", "Backend Analyst") }, true},
+		{"explicit format", nil, func(d *DOCX) { d.DublinCore.SetFormat(d.MimeType()) }, true},
+		{"office property", nil, func(d *DOCX) { d.Office.ContentStatus = "Final" }, true},
+		{"app property", nil, func(d *DOCX) { d.App.Manager = "Ana" }, true},
+		{"custom property", nil, func(d *DOCX) { d.SetCustomProperty("Vaga", "Backend") }, true},
+		{"title on a package without core part", map[string][]byte{corePropertiesPath: nil}, func(d *DOCX) { d.DublinCore.SetTitle("Hello") }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildPackage(t, "word", tt.overrides)
+			path := filepath.Join(t.TempDir(), "cv.docx")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			doc, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			tt.edit(doc)
+
+			changed, err := doc.Changed()
+			if err != nil {
+				t.Fatalf("Changed: %v", err)
+			}
+			if changed != tt.want {
+				t.Errorf("Changed = %v, want %v", changed, tt.want)
+			}
+
+			saved, err := doc.SaveIfChanged("")
+			if err != nil {
+				t.Fatalf("SaveIfChanged: %v", err)
+			}
+			if saved != tt.want {
+				t.Errorf("SaveIfChanged = %v, want %v", saved, tt.want)
+			}
+			stored, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rewritten := !bytes.Equal(stored, data); rewritten != tt.want {
+				t.Errorf("file rewritten = %v, want %v", rewritten, tt.want)
+			}
+		})
+	}
+
+	t.Run("other output", func(t *testing.T) {
+		dir := t.TempDir()
+		data := buildPackage(t, "word", nil)
+		path := filepath.Join(dir, "cv.docx")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		doc, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+
+		output := filepath.Join(dir, "copy.docx")
+		saved, err := doc.SaveIfChanged(output)
+		if err != nil || saved {
+			t.Fatalf("SaveIfChanged = %v, %v, want false, nil", saved, err)
+		}
+		if copied, err := os.ReadFile(output); err != nil || !bytes.Equal(copied, data) {
+			t.Errorf("unchanged copy isn't the original bytes (err %v)", err)
+		}
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cv.docx")
+		if err := os.WriteFile(path, buildPackage(t, "word", nil), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		doc, err := OpenReadOnly(path)
+		if err != nil {
+			t.Fatalf("OpenReadOnly: %v", err)
+		}
+		if _, err := doc.Changed(); err == nil {
+			t.Error("Changed succeeded on a read-only document, want an error")
+		}
+	})
+}
+
+func TestSaveRelatesCreatedCoreProperties(t *testing.T) {
+	withoutCore := func(t *testing.T, name string) []byte {
+		data, err := os.ReadFile(filepath.Join("testdata", "word", filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pattern := regexp.MustCompile(`<(Relationship|Override)[^>]*(core-properties|/docProps/core\.xml)[^>]*/>`)
+		return pattern.ReplaceAll(data, nil)
+	}
+	rels := func(target string) []byte {
+		return []byte(`<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+			`<Relationship Id="rId2" Type="` + coreRelationshipType + `" Target="` + target + `"/></Relationships>`)
+	}
+
+	tests := []struct {
+		name      string
+		overrides map[string][]byte
+		want      string // entry name of the created part
+	}{
+		{"no relationship", map[string][]byte{
+			corePropertiesPath: nil,
+			packageRelsPath:    withoutCore(t, packageRelsPath),
+			contentTypesPath:   withoutCore(t, contentTypesPath),
+		}, corePropertiesPath},
+		{"dangling relationship", map[string][]byte{
+			corePropertiesPath: nil,
+			packageRelsPath:    rels("/meta/props.xml"),
+			contentTypesPath:   withoutCore(t, contentTypesPath),
+		}, "meta/props.xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := buildPackage(t, "word", tt.overrides)
+			if strings.Contains(readPart(t, data, packageRelsPath), corePropertiesPath) {
+				t.Fatal("the input package still relates docProps/core.xml")
+			}
+			doc, err := OpenBytes(data)
+			if err != nil {
+				t.Fatalf("OpenBytes: %v", err)
+			}
+			doc.DublinCore.SetTitle("Hello")
+
+			var buf bytes.Buffer
+			if err := doc.SaveTo(&buf); err != nil {
+				t.Fatalf("SaveTo: %v", err)
+			}
+			saved := buf.Bytes()
+
+			reader, err := zip.NewReader(bytes.NewReader(saved), int64(len(saved)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := CorePartPath(reader); got != tt.want {
+				t.Errorf("CorePartPath after save = %q, want %q", got, tt.want)
+			}
+			if got := relationshipTarget(reader, coreRelationshipSuffix); got != tt.want {
+				t.Errorf("core-properties relationship targets %q, want %q", got, tt.want)
+			}
+			if got := strings.Count(readPart(t, saved, packageRelsPath), coreRelationshipType); got != 1 {
+				t.Errorf("_rels/.rels has %d core-properties relationships, want 1", got)
+			}
+			if types := readPart(t, saved, contentTypesPath); !strings.Contains(types, `PartName="/`+tt.want+`"`) {
+				t.Errorf("[Content_Types].xml doesn't register /%s:\n%s", tt.want, types)
+			}
+			if core := readPart(t, saved, tt.want); !strings.Contains(core, "<dc:title>Hello</dc:title>") {
+				t.Errorf("created %s = %s, want the edited title", tt.want, core)
+			}
+		})
+	}
+}
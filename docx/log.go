@@ -0,0 +1,38 @@
+package docx
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// currentLogger receives the warnings about fallbacks Open takes silently,
+// such as an unreadable app.xml or a dangling relationship
+var currentLogger atomic.Pointer[slog.Logger]
+
+func init() {
+	SetLogger(nil)
+}
+
+// SetLogger routes the package's parse warnings and fallbacks to l. A nil
+// logger restores the default, which discards everything. It is safe to call
+// while documents are being opened.
+func SetLogger(l *slog.Logger) {
+	if l == nil {
+		l = slog.New(discardHandler{})
+	}
+	currentLogger.Store(l)
+}
+
+// logger returns the logger set with SetLogger
+func logger() *slog.Logger {
+	return currentLogger.Load()
+}
+
+// discardHandler drops every record; it is disabled so callers skip building them
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
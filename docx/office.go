@@ -0,0 +1,104 @@
+package docx
+
+import (
+	"archive/zip"
+	"fmt"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+)
+
+// MIME types of the Office Open XML documents this package handles
+const (
+	MimeTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MimeTypePPTX = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	MimeTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// mainParts maps the main part of each Office application to its document MIME type.
+// Word, PowerPoint and Excel share docProps/core.xml, so only this part differs.
+var mainParts = []struct {
+	path     string
+	mimeType string
+}{
+	{"word/document.xml", MimeTypeDOCX},
+	{"ppt/presentation.xml", MimeTypePPTX},
+	{"xl/workbook.xml", MimeTypeXLSX},
+}
+
+// DocumentType returns the MIME type of the Office document in reader,
+// identified by its main part
+func DocumentType(reader *zip.Reader) (string, error) {
+	for _, part := range mainParts {
+		if _, err := ziputil.FindFile(reader, part.path); err == nil {
+			return part.mimeType, nil
+		}
+	}
+
+	paths := make([]string, len(mainParts))
+	for i, part := range mainParts {
+		paths[i] = part.path
+	}
+	return "", fmt.Errorf("%w: missing %s", ErrInvalidPackage, strings.Join(paths, ", "))
+}
+
+// OfficeProperties are the core.xml properties defined by the Open Packaging
+// Conventions rather than Dublin Core
+type OfficeProperties struct {
+	ContentStatus  string // cp:contentStatus, e.g. "Draft" or "Final"
+	Revision       string // cp:revision, incremented by Word on every save
+	LastModifiedBy string // cp:lastModifiedBy
+	Version        string // cp:version
+}
+
+// officePropertyNames are the cp: element names of OfficeProperties, in core.xml order
+var officePropertyNames = []string{"lastModifiedBy", "revision", "contentStatus", "version"}
+
+// OfficePropertyNames returns the names accepted by OfficeProperties.Get and Set
+func OfficePropertyNames() []string {
+	return append([]string{}, officePropertyNames...)
+}
+
+// Get returns the value of the named property, e.g. "contentStatus"
+func (p *OfficeProperties) Get(name string) (string, error) {
+	field := p.field(name)
+	if field == nil {
+		return "", fmt.Errorf("unknown core property: %s", name)
+	}
+	return *field, nil
+}
+
+// Set replaces the value of the named property; an empty value removes it
+func (p *OfficeProperties) Set(name, value string) error {
+	field := p.field(name)
+	if field == nil {
+		return fmt.Errorf("unknown core property: %s", name)
+	}
+	*field = value
+	return nil
+}
+
+// field maps a property name, in any case, to the matching struct field
+func (p *OfficeProperties) field(name string) *string {
+	switch strings.ToLower(name) {
+	case "contentstatus":
+		return &p.ContentStatus
+	case "revision":
+		return &p.Revision
+	case "lastmodifiedby":
+		return &p.LastModifiedBy
+	case "version":
+		return &p.Version
+	}
+	return nil
+}
+
+// isOfficeProperty reports whether a cp: element is modeled by OfficeProperties
+func isOfficeProperty(local string) bool {
+	for _, name := range officePropertyNames {
+		if local == name {
+			return true
+		}
+	}
+	return false
+}
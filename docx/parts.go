@@ -0,0 +1,152 @@
+package docx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/internal/rawxml"
+)
+
+const contentTypesPath = "[Content_Types].xml"
+
+// PartInfo describes one zip entry of a document package
+type PartInfo struct {
+	Name             string
+	CompressedSize   uint64
+	UncompressedSize uint64
+	Method           string // "store", "deflate" or "method N" for others
+}
+
+// ReadParts lists the entries of reader in archive order. Unlike Open it
+// needs no valid package, so it can show why metadata isn't found.
+func ReadParts(reader *zip.Reader) []PartInfo {
+	parts := make([]PartInfo, 0, len(reader.File))
+	for _, file := range reader.File {
+		parts = append(parts, PartInfo{
+			Name:             file.Name,
+			CompressedSize:   file.CompressedSize64,
+			UncompressedSize: file.UncompressedSize64,
+			Method:           methodName(file.Method),
+		})
+	}
+	return parts
+}
+
+// methodName names a zip compression method
+func methodName(method uint16) string {
+	switch method {
+	case zip.Store:
+		return "store"
+	case zip.Deflate:
+		return "deflate"
+	}
+	return fmt.Sprintf("method %d", method)
+}
+
+// packagePart is a package-level XML part such as [Content_Types].xml or
+// _rels/.rels, kept verbatim apart from the entries added to it
+type packagePart struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr       `xml:",any,attr"`
+	Elements []rawxml.Element `xml:",any"`
+}
+
+// parsePackagePart parses a package-level part
+func parsePackagePart(data []byte) (*packagePart, error) {
+	var part packagePart
+	if err := xml.Unmarshal(data, &part); err != nil {
+		return nil, fmt.Errorf("XML parsing failed: %w", err)
+	}
+	return &part, nil
+}
+
+// toXML serializes the part with the namespace prefixes it declared
+func (p *packagePart) toXML() ([]byte, error) {
+	prefixes := rawxml.Prefixes(p.Attrs)
+
+	out := &packagePart{XMLName: rawxml.QualifyName(p.XMLName, prefixes)}
+	for _, attr := range p.Attrs {
+		out.Attrs = append(out.Attrs, rawxml.QualifyAttr(attr, prefixes))
+	}
+	for _, element := range p.Elements {
+		out.Elements = append(out.Elements, element.WithPrefixes(prefixes))
+	}
+
+	header := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+	data, err := xml.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(header + string(data)), nil
+}
+
+// attr returns the value of the element's attribute with the given local name
+func attr(element rawxml.Element, name string) string {
+	for _, a := range element.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// addContentTypeOverride registers the content type of a part unless an
+// Override for it already exists
+func addContentTypeOverride(data []byte, partName, contentType string) ([]byte, error) {
+	types, err := parsePackagePart(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, element := range types.Elements {
+		if element.XMLName.Local == "Override" && strings.EqualFold(attr(element, "PartName"), partName) {
+			return data, nil
+		}
+	}
+
+	types.Elements = append(types.Elements, rawxml.Element{
+		XMLName: xml.Name{Local: "Override"},
+		Attrs: []xml.Attr{
+			{Name: xml.Name{Local: "PartName"}, Value: partName},
+			{Name: xml.Name{Local: "ContentType"}, Value: contentType},
+		},
+	})
+	return types.toXML()
+}
+
+// addPackageRelationship adds a relationship of relType to target unless
+// the package already has one of that type
+func addPackageRelationship(data []byte, relType, target string) ([]byte, error) {
+	rels, err := parsePackagePart(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	for _, element := range rels.Elements {
+		if attr(element, "Type") == relType {
+			return data, nil
+		}
+		ids[attr(element, "Id")] = true
+	}
+
+	id := ""
+	for n := len(rels.Elements) + 1; ; n++ {
+		if id = fmt.Sprintf("rId%d", n); !ids[id] {
+			break
+		}
+	}
+
+	rels.Elements = append(rels.Elements, rawxml.Element{
+		XMLName: xml.Name{Local: "Relationship"},
+		Attrs: []xml.Attr{
+			{Name: xml.Name{Local: "Id"}, Value: id},
+			{Name: xml.Name{Local: "Type"}, Value: relType},
+			{Name: xml.Name{Local: "Target"}, Value: target},
+		},
+	})
+	return rels.toXML()
+}
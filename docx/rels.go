@@ -0,0 +1,95 @@
+package docx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+)
+
+const (
+	packageRelsPath = "_rels/.rels"
+
+	coreRelationshipType = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties"
+
+	// emptyPackageRels is the _rels/.rels written for a package that has none
+	emptyPackageRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`
+
+	// coreRelationshipSuffix ends the core-properties relationship type in
+	// both the transitional and the strict OOXML namespaces
+	coreRelationshipSuffix = "/metadata/core-properties"
+)
+
+// packageRelationships is the root of _rels/.rels
+type packageRelationships struct {
+	Relationships []struct {
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// CorePartPath returns the zip entry name of the core properties part, as
+// Open finds it. The target of the core-properties relationship in
+// _rels/.rels wins over docProps/core.xml, and names are matched regardless
+// of case.
+func CorePartPath(reader *zip.Reader) string {
+	return partPath(reader, coreRelationshipSuffix, corePropertiesPath)
+}
+
+// partPath returns the entry name of the part targeted by the package
+// relationship whose type ends with suffix, or fallback if there is none or
+// its target isn't in the archive while fallback is
+func partPath(reader *zip.Reader, suffix, fallback string) string {
+	candidates := []string{fallback}
+	if target := relationshipTarget(reader, suffix); target != "" {
+		candidates = []string{target, fallback}
+	}
+
+	// Report the name as stored in the archive so Save can match it exactly
+	for i, name := range candidates {
+		if file, err := ziputil.FindFile(reader, name); err == nil {
+			if i > 0 {
+				logger().Warn("relationship target not found, using the default part",
+					"relationship", suffix, "target", candidates[0], "part", file.Name)
+			}
+			return file.Name
+		}
+	}
+	return candidates[0]
+}
+
+// relationshipTarget returns the part targeted by the first package
+// relationship whose type ends with suffix, or "" if there is none
+func relationshipTarget(reader *zip.Reader, suffix string) string {
+	relsFile, err := ziputil.FindFile(reader, packageRelsPath)
+	if err != nil {
+		return ""
+	}
+	data, err := ziputil.ReadFile(relsFile)
+	if err != nil {
+		logger().Warn("failed to read package relationships, using default part names", "part", relsFile.Name, "error", err)
+		return ""
+	}
+
+	var rels packageRelationships
+	if err := xml.Unmarshal(data, &rels); err != nil {
+		logger().Warn("failed to parse package relationships, using default part names", "part", relsFile.Name, "error", err)
+		return ""
+	}
+	for _, rel := range rels.Relationships {
+		if strings.HasSuffix(rel.Type, suffix) && rel.Target != "" {
+			// Targets are URIs: "core%20props.xml" names the entry "core props.xml"
+			target := rel.Target
+			if unescaped, err := url.PathUnescape(target); err == nil {
+				target = unescaped
+			}
+			// Package relationship targets are relative to the package root
+			return strings.TrimPrefix(path.Clean("/"+target), "/")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,245 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+)
+
+// wordprocessingNamespaces are the w: namespaces of transitional and strict OOXML
+var wordprocessingNamespaces = map[string]bool{
+	"http://schemas.openxmlformats.org/wordprocessingml/2006/main": true,
+	"http://purl.oclc.org/ooxml/wordprocessingml/main":             true,
+}
+
+// documentRelationshipSuffix ends the main-part relationship type in both
+// the transitional and the strict OOXML namespaces
+const documentRelationshipSuffix = "/officeDocument"
+
+// stylesPath is where Word keeps the style definitions
+const stylesPath = "word/styles.xml"
+
+// paragraph is the text of a w:p with the style and outline level it declares
+type paragraph struct {
+	style   string // w:pStyle, a style id such as "Heading1"
+	outline string // w:outlineLvl, "0" for a top-level heading
+	text    strings.Builder
+}
+
+// ExtractText returns the plain text of a Word document's body: the text of
+// its runs, one line per paragraph, with tabs and line breaks kept. Field
+// codes, deleted revisions and formatting are dropped. Documents opened with
+// OpenReadOnly are read again from FilePath.
+func (d *DOCX) ExtractText() (string, error) {
+	var lines []string
+	err := d.withBody(func(reader *zip.Reader, paragraphs []*paragraph) error {
+		for _, p := range paragraphs {
+			lines = append(lines, p.text.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n"), nil
+}
+
+// HeadingText returns the text of the first non-empty Heading 1 paragraph,
+// or of the first non-empty paragraph when there is no such heading. A
+// paragraph is a Heading 1 when its style is named "heading 1" in
+// word/styles.xml, whatever its id in a localized Word, or when it is at
+// outline level 0. It returns "" for a document without text.
+func (d *DOCX) HeadingText() (string, error) {
+	var heading string
+	err := d.withBody(func(reader *zip.Reader, paragraphs []*paragraph) error {
+		headingStyles := headingStyleIDs(reader)
+
+		for _, p := range paragraphs {
+			text := strings.Join(strings.Fields(p.text.String()), " ")
+			if text == "" {
+				continue
+			}
+			if headingStyles[p.style] || p.outline == "0" {
+				heading = text
+				return nil
+			}
+			if heading == "" {
+				heading = text
+			}
+		}
+		return nil
+	})
+	return heading, err
+}
+
+// withBody calls fn with the document's archive and the paragraphs of its main
+// part, reading FileData or, for documents opened read-only, FilePath again
+func (d *DOCX) withBody(fn func(*zip.Reader, []*paragraph) error) error {
+	if d.mimeType != MimeTypeDOCX {
+		return fmt.Errorf("text extraction supports Word documents only, not %s", d.mimeType)
+	}
+
+	var reader *zip.Reader
+	if d.FileData != nil {
+		r, err := zip.NewReader(bytes.NewReader(d.FileData), int64(len(d.FileData)))
+		if err != nil {
+			return fmt.Errorf("failed to create zip reader: %w", err)
+		}
+		reader = r
+	} else {
+		if d.FilePath == "" {
+			return errors.New("no document content to read text from")
+		}
+		r, err := zip.OpenReader(d.FilePath)
+		if err != nil {
+			return fmt.Errorf("failed to create zip reader: %w", err)
+		}
+		defer r.Close()
+		reader = &r.Reader
+	}
+
+	name := partPath(reader, documentRelationshipSuffix, "word/document.xml")
+	file, err := ziputil.FindFile(reader, name)
+	if err != nil {
+		return err
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	paragraphs, err := readParagraphs(rc)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return fn(reader, paragraphs)
+}
+
+// readParagraphs streams a WordprocessingML part and collects the text of the
+// w:t elements of each w:p, in the order the paragraphs end
+func readParagraphs(r io.Reader) ([]*paragraph, error) {
+	var paragraphs []*paragraph
+	var open []*paragraph // paragraphs in text boxes nest inside their anchor's
+	decoder := xml.NewDecoder(r)
+	inText := false
+	inTabStops := false // w:tab also defines tab stops inside w:tabs
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var current *paragraph
+		if len(open) > 0 {
+			current = open[len(open)-1]
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if !wordprocessingNamespaces[t.Name.Space] {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				open = append(open, &paragraph{})
+			case "pStyle":
+				if current != nil {
+					current.style = wordAttr(t, "val")
+				}
+			case "outlineLvl":
+				if current != nil {
+					current.outline = wordAttr(t, "val")
+				}
+			case "t":
+				inText = true
+			case "tabs":
+				inTabStops = true
+			case "tab":
+				if current != nil && !inTabStops {
+					current.text.WriteByte('\t')
+				}
+			case "br", "cr":
+				if current != nil {
+					current.text.WriteByte('\n')
+				}
+			}
+		case xml.EndElement:
+			if !wordprocessingNamespaces[t.Name.Space] {
+				continue
+			}
+			switch t.Name.Local {
+			case "p":
+				if current != nil {
+					paragraphs = append(paragraphs, current)
+					open = open[:len(open)-1]
+				}
+			case "t":
+				inText = false
+			case "tabs":
+				inTabStops = false
+			}
+		case xml.CharData:
+			if inText && current != nil {
+				current.text.Write(t)
+			}
+		}
+	}
+
+	return paragraphs, nil
+}
+
+// headingStyleIDs returns the ids of the paragraph styles named "heading 1"
+// in word/styles.xml, plus Word's default id for documents without styles
+func headingStyleIDs(reader *zip.Reader) map[string]bool {
+	ids := map[string]bool{"Heading1": true}
+
+	file, err := ziputil.FindFile(reader, stylesPath)
+	if err != nil {
+		return ids
+	}
+	data, err := ziputil.ReadFile(file)
+	if err != nil {
+		logger().Warn("failed to read styles, using the default heading style", "part", file.Name, "error", err)
+		return ids
+	}
+
+	var styles struct {
+		Styles []struct {
+			ID   string `xml:"styleId,attr"`
+			Name struct {
+				Value string `xml:"val,attr"`
+			} `xml:"name"`
+		} `xml:"style"`
+	}
+	if err := xml.Unmarshal(data, &styles); err != nil {
+		logger().Warn("failed to parse styles, using the default heading style", "part", file.Name, "error", err)
+		return ids
+	}
+	for _, style := range styles.Styles {
+		if strings.EqualFold(style.Name.Value, "heading 1") {
+			ids[style.ID] = true
+		}
+	}
+	return ids
+}
+
+// wordAttr returns the value of a w: attribute of element
+func wordAttr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name && wordprocessingNamespaces[a.Name.Space] {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package dublincore
+
+import (
+	"fmt"
+	"slices"
+	"unicode/utf8"
+)
+
+// Constraints are policy limits a downstream system puts on the metadata,
+// keyed by field name. Fields without an entry, or with a limit of 0, are unlimited.
+type Constraints struct {
+	MaxLength map[string]int // maximum characters in each value of the field
+	MaxCount  map[string]int // maximum number of values of the field
+}
+
+// ConstraintError describes a field that exceeds a limit of Constraints
+type ConstraintError struct {
+	Field  string
+	Limit  int
+	Actual int
+	Count  bool // the limit is on the number of values rather than their length
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Count {
+		return fmt.Sprintf("%s: %d values exceed the limit of %d", e.Field, e.Actual, e.Limit)
+	}
+	return fmt.Sprintf("%s: a value of %d characters exceeds the limit of %d", e.Field, e.Actual, e.Limit)
+}
+
+// ValidateConstraints checks dc against the limits of c and returns one error
+// per violation, or per unknown field name in c. Lengths count characters,
+// not bytes.
+func (dc *DublinCore) ValidateConstraints(c Constraints) []error {
+	var errs []error
+
+	for _, name := range sortedKeys(c.MaxCount) {
+		values, err := dc.Get(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if limit := c.MaxCount[name]; limit > 0 && len(values) > limit {
+			errs = append(errs, &ConstraintError{Field: name, Limit: limit, Actual: len(values), Count: true})
+		}
+	}
+
+	for _, name := range sortedKeys(c.MaxLength) {
+		values, err := dc.Get(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		limit := c.MaxLength[name]
+		if limit <= 0 {
+			continue
+		}
+		for _, value := range values {
+			if length := utf8.RuneCountInString(value); length > limit {
+				errs = append(errs, &ConstraintError{Field: name, Limit: limit, Actual: length})
+			}
+		}
+	}
+
+	return errs
+}
+
+// sortedKeys returns the keys of m in order, so errors are reported deterministically
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,35 @@
+package dublincore
+
+import "strings"
+
+// dcmiTypes is the DCMI Type Vocabulary (http://purl.org/dc/dcmitype/)
+var dcmiTypes = []string{
+	"Collection", "Dataset", "Event", "Image", "InteractiveResource", "MovingImage",
+	"PhysicalObject", "Service", "Software", "Sound", "StillImage", "Text",
+}
+
+// DCMITypes returns the terms of the DCMI Type Vocabulary
+func DCMITypes() []string {
+	return append([]string{}, dcmiTypes...)
+}
+
+// DCMIType returns the vocabulary term matching value ignoring case, e.g.
+// "stillimage" gives "StillImage", and whether there is one
+func DCMIType(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, term := range dcmiTypes {
+		if strings.EqualFold(term, value) {
+			return term, true
+		}
+	}
+	return value, false
+}
+
+// SetType sets the nature of the resource. A DCMI Type Vocabulary term is
+// stored in its canonical spelling; other values are stored verbatim and
+// SetType returns false.
+func (dc *DublinCore) SetType(value string) bool {
+	term, ok := DCMIType(value)
+	dc.Type = []string{term}
+	return ok
+}
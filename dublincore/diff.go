@@ -0,0 +1,85 @@
+package dublincore
+
+// FieldDiff describes how a single element differs between two records
+type FieldDiff struct {
+	Field   string
+	Before  []string
+	After   []string
+	Added   []string // values only present in After
+	Removed []string // values only present in Before
+}
+
+// Diff compares a and b field by field and returns the fields whose values
+// differ, in FieldNames order. A pure reordering is reported with empty
+// Added/Removed slices.
+func Diff(a, b *DublinCore) []FieldDiff {
+	var diffs []FieldDiff
+	for _, name := range fieldNames {
+		before := *a.field(name)
+		after := *b.field(name)
+		if equalValues(before, after) {
+			continue
+		}
+		diffs = append(diffs, FieldDiff{
+			Field:   name,
+			Before:  before,
+			After:   after,
+			Added:   missingFrom(after, before),
+			Removed: missingFrom(before, after),
+		})
+	}
+	return diffs
+}
+
+// Equal reports whether dc and other hold the same values in the same order
+// for every field, including language-tagged titles and descriptions
+func (dc *DublinCore) Equal(other *DublinCore) bool {
+	if dc == nil || other == nil {
+		return dc == other
+	}
+	if len(Diff(dc, other)) > 0 {
+		return false
+	}
+	return equalLang(dc.TitleLang, other.TitleLang) && equalLang(dc.DescriptionLang, other.DescriptionLang)
+}
+
+// equalLang compares two tagged value lists in order, treating nil and empty as equal
+func equalLang(a, b []LangString) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// equalValues compares two value lists in order, treating nil and empty as equal
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// missingFrom returns the values of src that don't appear in other
+func missingFrom(src, other []string) []string {
+	present := make(map[string]bool, len(other))
+	for _, value := range other {
+		present[value] = true
+	}
+	var missing []string
+	for _, value := range src {
+		if !present[value] {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
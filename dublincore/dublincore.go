@@ -1,39 +1,62 @@
 package dublincore
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
 // DublinCore represents the Dublin Core metadata elements
 type DublinCore struct {
-	XMLName     xml.Name `xml:"http://purl.org/dc/elements/1.1/ dc"`
-	Title       []string `xml:"title,omitempty"`
-	Creator     []string `xml:"creator,omitempty"`
-	Subject     []string `xml:"subject,omitempty"`
-	Description []string `xml:"description,omitempty"`
-	Publisher   []string `xml:"publisher,omitempty"`
-	Contributor []string `xml:"contributor,omitempty"`
-	Date        []string `xml:"date,omitempty"`
-	Type        []string `xml:"type,omitempty"`
-	Format      []string `xml:"format,omitempty"`
-	Identifier  []string `xml:"identifier,omitempty"`
-	Source      []string `xml:"source,omitempty"`
-	Language    []string `xml:"language,omitempty"`
-	Relation    []string `xml:"relation,omitempty"`
-	Coverage    []string `xml:"coverage,omitempty"`
-	Rights      []string `xml:"rights,omitempty"`
+	XMLName     xml.Name `xml:"http://purl.org/dc/elements/1.1/ dc" json:"-"`
+	Title       []string `xml:"title,omitempty" json:"title,omitempty"`
+	Creator     []string `xml:"creator,omitempty" json:"creator,omitempty"`
+	Subject     []string `xml:"subject,omitempty" json:"subject,omitempty"`
+	Description []string `xml:"description,omitempty" json:"description,omitempty"`
+	Publisher   []string `xml:"publisher,omitempty" json:"publisher,omitempty"`
+	Contributor []string `xml:"contributor,omitempty" json:"contributor,omitempty"`
+	Date        []string `xml:"date,omitempty" json:"date,omitempty"`
+	Type        []string `xml:"type,omitempty" json:"type,omitempty"`
+	Format      []string `xml:"format,omitempty" json:"format,omitempty"`
+	Identifier  []string `xml:"identifier,omitempty" json:"identifier,omitempty"`
+	Source      []string `xml:"source,omitempty" json:"source,omitempty"`
+	Language    []string `xml:"language,omitempty" json:"language,omitempty"`
+	Relation    []string `xml:"relation,omitempty" json:"relation,omitempty"`
+	Coverage    []string `xml:"coverage,omitempty" json:"coverage,omitempty"`
+	Rights      []string `xml:"rights,omitempty" json:"rights,omitempty"`
+
+	// Language-tagged alternatives of Title and Description (xml:lang)
+	TitleLang       []LangString `xml:"-" json:"title_lang,omitempty"`
+	DescriptionLang []LangString `xml:"-" json:"description_lang,omitempty"`
 
 	// Custom fields for CP namespace
-	Keywords []string `xml:"http://purl.org/dc/terms/ keyword,omitempty"`
-	Category []string `xml:"http://purl.org/dc/terms/ type,omitempty"` // Using type for category
+	Keywords []string `xml:"http://schemas.openxmlformats.org/package/2006/metadata/core-properties keywords,omitempty" json:"keywords,omitempty"`
+	Category []string `xml:"http://schemas.openxmlformats.org/package/2006/metadata/core-properties category,omitempty" json:"category,omitempty"`
+
+	// formatSet records that Format was given explicitly rather than filled
+	// in from the type of the file, see FormatSet
+	formatSet bool
+}
+
+// ErrMalformedMetadata is returned when a document's metadata part exists but cannot be parsed
+var ErrMalformedMetadata = errors.New("metadata could not be parsed")
+
+// fieldNames lists the element names accepted by Get and Set, in struct order
+var fieldNames = []string{
+	"title", "creator", "subject", "description", "publisher", "contributor",
+	"date", "type", "format", "identifier", "source", "language", "relation",
+	"coverage", "rights", "keywords", "category",
 }
 
-// New creates a new DublinCore instance with default values
+// New creates a new DublinCore instance with default values. Format is left
+// empty: document packages set it from the type of the file they open.
 func New() *DublinCore {
 	return &DublinCore{
 		Date:     []string{time.Now().Format(time.RFC3339)},
-		Format:   []string{"application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
 		Category: []string{"curriculo"}, // Default category
 	}
 }
@@ -48,21 +71,282 @@ func (dc *DublinCore) AddCreator(creator string) {
 	dc.Creator = append(dc.Creator, creator)
 }
 
+// RemoveCreator removes every creator matching name, ignoring case and surrounding spaces
+func (dc *DublinCore) RemoveCreator(name string) {
+	dc.Creator = removeValue(dc.Creator, name)
+}
+
+// SetPublisher sets the publisher
+func (dc *DublinCore) SetPublisher(publisher string) {
+	dc.Publisher = []string{publisher}
+}
+
+// AddPublisher adds a publisher
+func (dc *DublinCore) AddPublisher(publisher string) {
+	dc.Publisher = append(dc.Publisher, publisher)
+}
+
+// SetContributor sets the contributor
+func (dc *DublinCore) SetContributor(contributor string) {
+	dc.Contributor = []string{contributor}
+}
+
+// AddContributor adds a contributor
+func (dc *DublinCore) AddContributor(contributor string) {
+	dc.Contributor = append(dc.Contributor, contributor)
+}
+
+// SetFormat sets the format, usually the MIME type of the document
+func (dc *DublinCore) SetFormat(format string) {
+	dc.Format = []string{format}
+	dc.formatSet = true
+}
+
+// FormatSet reports whether Format was given explicitly, through SetFormat,
+// Set, SetAll or Merge, rather than assigned directly as document packages do
+// when they default it to the file type. Formats that can't infer the format
+// from the file, such as Office core.xml, only write an explicit one.
+func (dc *DublinCore) FormatSet() bool {
+	return dc.formatSet
+}
+
+// SetCoverage sets the spatial or temporal coverage
+func (dc *DublinCore) SetCoverage(coverage string) {
+	dc.Coverage = []string{coverage}
+}
+
+// AddCoverage adds a spatial or temporal coverage
+func (dc *DublinCore) AddCoverage(coverage string) {
+	dc.Coverage = append(dc.Coverage, coverage)
+}
+
+// SetSource sets the work the document is derived from; an empty source clears it
+func (dc *DublinCore) SetSource(source string) {
+	dc.Source = nil
+	dc.AddSource(source)
+}
+
+// AddSource adds a work the document is derived from, skipping empty values
+func (dc *DublinCore) AddSource(source string) {
+	if source = strings.TrimSpace(source); source != "" {
+		dc.Source = append(dc.Source, source)
+	}
+}
+
 // SetDescription sets the description
 func (dc *DublinCore) SetDescription(description string) {
 	dc.Description = []string{description}
 }
 
+// SetDate sets the date and time in W3CDTF (RFC3339), keeping the offset of t
+func (dc *DublinCore) SetDate(t time.Time) {
+	dc.Date = []string{t.Format(time.RFC3339)}
+}
+
+// SetDay sets the date in W3CDTF as a plain date (YYYY-MM-DD), without a time of day
+func (dc *DublinCore) SetDay(t time.Time) {
+	dc.Date = []string{t.Format(time.DateOnly)}
+}
+
 // AddKeyword adds a keyword
 func (dc *DublinCore) AddKeyword(keyword string) {
 	dc.Keywords = append(dc.Keywords, keyword)
 }
 
+// RemoveKeyword removes every keyword matching keyword, ignoring case and surrounding spaces
+func (dc *DublinCore) RemoveKeyword(keyword string) {
+	dc.Keywords = removeValue(dc.Keywords, keyword)
+}
+
 // SetCategory sets the category (always to "curriculo")
 func (dc *DublinCore) SetCategory() {
 	dc.Category = []string{"curriculo"}
 }
 
+// normalizedFields are the repeatable fields cleaned up by Normalize
+var normalizedFields = []string{"creator", "subject", "contributor", "keywords"}
+
+// Normalize trims the values of Creator, Subject, Contributor and Keywords,
+// drops empty ones and removes duplicates ignoring case, keeping the first
+// occurrence in its original position
+func (dc *DublinCore) Normalize() {
+	for _, name := range normalizedFields {
+		field := dc.field(name)
+		var kept []string
+		for _, value := range *field {
+			value = strings.TrimSpace(value)
+			if value == "" || containsFold(kept, value) {
+				continue
+			}
+			kept = append(kept, value)
+		}
+		*field = kept
+	}
+}
+
+// LowercaseKeywords lowercases every keyword, merging those that only differed in case
+func (dc *DublinCore) LowercaseKeywords() {
+	for i, keyword := range dc.Keywords {
+		dc.Keywords[i] = strings.ToLower(keyword)
+	}
+	dc.Normalize()
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeValue returns values without the entries matching value; a value that isn't present is a no-op
+func removeValue(values []string, value string) []string {
+	value = strings.TrimSpace(value)
+	var kept []string
+	for _, v := range values {
+		if !strings.EqualFold(strings.TrimSpace(v), value) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// FieldNames returns the element names accepted by Get and Set
+func FieldNames() []string {
+	return append([]string{}, fieldNames...)
+}
+
+// Get returns the values of the named element (e.g. "title", "keywords")
+func (dc *DublinCore) Get(name string) ([]string, error) {
+	field := dc.field(name)
+	if field == nil {
+		return nil, fmt.Errorf("unknown field: %s", name)
+	}
+	return *field, nil
+}
+
+// Set replaces the values of the named element
+func (dc *DublinCore) Set(name string, values []string) error {
+	field := dc.field(name)
+	if field == nil {
+		return fmt.Errorf("unknown field: %s", name)
+	}
+	*field = values
+	dc.markSet(name)
+	return nil
+}
+
+// markSet records an explicit assignment of the named field
+func (dc *DublinCore) markSet(name string) {
+	if dc.field(name) == &dc.Format {
+		dc.formatSet = true
+	}
+}
+
+// SetAll replaces the values of every element named in fields, e.g. from a
+// parsed form or config file. Names are checked first: if any is unknown,
+// nothing is changed and the error lists all of them.
+func (dc *DublinCore) SetAll(fields map[string][]string) error {
+	var unknown []string
+	for name := range fields {
+		if dc.field(name) == nil {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return fmt.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+
+	for name, values := range fields {
+		*dc.field(name) = values
+		dc.markSet(name)
+	}
+	return nil
+}
+
+// Merge overlays the non-empty fields of other onto dc. With overwrite the
+// values replace the receiver's; otherwise they are appended, skipping values
+// already present. Empty fields in other never clear existing data.
+func (dc *DublinCore) Merge(other *DublinCore, overwrite bool) {
+	for _, name := range fieldNames {
+		values := *other.field(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		field := dc.field(name)
+		dc.markSet(name)
+		if overwrite {
+			*field = append([]string{}, values...)
+			continue
+		}
+		*field = append(*field, missingFrom(values, *field)...)
+	}
+	dc.mergeLang(other, overwrite)
+}
+
+// Clone returns a deep copy of dc whose slices can be modified independently
+func (dc *DublinCore) Clone() *DublinCore {
+	clone := &DublinCore{XMLName: dc.XMLName, formatSet: dc.formatSet}
+	for _, name := range fieldNames {
+		if values := *dc.field(name); values != nil {
+			*clone.field(name) = append([]string{}, values...)
+		}
+	}
+	if dc.TitleLang != nil {
+		clone.TitleLang = append([]LangString{}, dc.TitleLang...)
+	}
+	if dc.DescriptionLang != nil {
+		clone.DescriptionLang = append([]LangString{}, dc.DescriptionLang...)
+	}
+	return clone
+}
+
+// field maps an element name to its slice, or nil if the name is unknown
+func (dc *DublinCore) field(name string) *[]string {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "title":
+		return &dc.Title
+	case "creator":
+		return &dc.Creator
+	case "subject":
+		return &dc.Subject
+	case "description":
+		return &dc.Description
+	case "publisher":
+		return &dc.Publisher
+	case "contributor":
+		return &dc.Contributor
+	case "date":
+		return &dc.Date
+	case "type":
+		return &dc.Type
+	case "format":
+		return &dc.Format
+	case "identifier":
+		return &dc.Identifier
+	case "source":
+		return &dc.Source
+	case "language":
+		return &dc.Language
+	case "relation":
+		return &dc.Relation
+	case "coverage":
+		return &dc.Coverage
+	case "rights":
+		return &dc.Rights
+	case "keywords":
+		return &dc.Keywords
+	case "category":
+		return &dc.Category
+	}
+	return nil
+}
+
 // ToXML converts Dublin Core metadata to XML
 func (dc *DublinCore) ToXML() ([]byte, error) {
 	return xml.MarshalIndent(dc, "", "  ")
@@ -77,3 +361,18 @@ func FromXML(data []byte) (*DublinCore, error) {
 	}
 	return &dc, nil
 }
+
+// ToJSON converts Dublin Core metadata to JSON, keyed by lowercase element names
+func (dc *DublinCore) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(dc, "", "  ")
+}
+
+// FromJSON parses Dublin Core metadata from JSON
+func FromJSON(data []byte) (*DublinCore, error) {
+	var dc DublinCore
+	err := json.Unmarshal(data, &dc)
+	if err != nil {
+		return nil, err
+	}
+	return &dc, nil
+}
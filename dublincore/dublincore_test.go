@@ -0,0 +1,205 @@
+package dublincore
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fieldTests reads each field of a DublinCore straight from the struct, so
+// Get and Set are checked against the field they claim to access
+var fieldTests = []struct {
+	name  string
+	field func(*DublinCore) []string
+}{
+	{"title", func(dc *DublinCore) []string { return dc.Title }},
+	{"creator", func(dc *DublinCore) []string { return dc.Creator }},
+	{"subject", func(dc *DublinCore) []string { return dc.Subject }},
+	{"description", func(dc *DublinCore) []string { return dc.Description }},
+	{"publisher", func(dc *DublinCore) []string { return dc.Publisher }},
+	{"contributor", func(dc *DublinCore) []string { return dc.Contributor }},
+	{"date", func(dc *DublinCore) []string { return dc.Date }},
+	{"type", func(dc *DublinCore) []string { return dc.Type }},
+	{"format", func(dc *DublinCore) []string { return dc.Format }},
+	{"identifier", func(dc *DublinCore) []string { return dc.Identifier }},
+	{"source", func(dc *DublinCore) []string { return dc.Source }},
+	{"language", func(dc *DublinCore) []string { return dc.Language }},
+	{"relation", func(dc *DublinCore) []string { return dc.Relation }},
+	{"coverage", func(dc *DublinCore) []string { return dc.Coverage }},
+	{"rights", func(dc *DublinCore) []string { return dc.Rights }},
+	{"keywords", func(dc *DublinCore) []string { return dc.Keywords }},
+	{"category", func(dc *DublinCore) []string { return dc.Category }},
+}
+
+func TestFieldTestsCoverFieldNames(t *testing.T) {
+	var names []string
+	for _, tt := range fieldTests {
+		names = append(names, tt.name)
+	}
+	if !slices.Equal(names, FieldNames()) {
+		t.Errorf("fieldTests cover %q, want %q", names, FieldNames())
+	}
+}
+
+func TestGetSetRoundTrip(t *testing.T) {
+	for _, tt := range fieldTests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := &DublinCore{}
+			values := []string{tt.name + " one", tt.name + " two"}
+
+			if err := dc.Set(tt.name, values); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			if got := tt.field(dc); !slices.Equal(got, values) {
+				t.Errorf("struct field = %q, want %q", got, values)
+			}
+			got, err := dc.Get(tt.name)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if !slices.Equal(got, values) {
+				t.Errorf("Get = %q, want %q", got, values)
+			}
+
+			// Only the named field changes
+			for _, other := range fieldTests {
+				if other.name != tt.name && len(other.field(dc)) > 0 {
+					t.Errorf("Set(%q) also set %s to %q", tt.name, other.name, other.field(dc))
+				}
+			}
+
+			if err := dc.Set(tt.name, nil); err != nil {
+				t.Fatalf("Set nil: %v", err)
+			}
+			if got, _ := dc.Get(tt.name); len(got) != 0 {
+				t.Errorf("Get after clearing = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestGetSetUnknownField(t *testing.T) {
+	dc := &DublinCore{}
+	if _, err := dc.Get("author"); err == nil {
+		t.Error("Get(author) succeeded, want an error")
+	}
+	if err := dc.Set("author", []string{"Ana"}); err == nil {
+		t.Error("Set(author) succeeded, want an error")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	base := &DublinCore{}
+	for _, tt := range fieldTests {
+		base.Set(tt.name, []string{tt.name + " a", tt.name + " b"})
+	}
+	base.SetLangTitle("en", "Title")
+	base.SetLangDescription("en", "Description")
+
+	if !base.Equal(base.Clone()) {
+		t.Fatal("a record isn't equal to its clone")
+	}
+
+	for _, tt := range fieldTests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base.Clone()
+			changed.Set(tt.name, []string{tt.name + " a", tt.name + " c"})
+			if base.Equal(changed) {
+				t.Error("Equal ignores a changed value")
+			}
+
+			reordered := base.Clone()
+			reordered.Set(tt.name, []string{tt.name + " b", tt.name + " a"})
+			if base.Equal(reordered) {
+				t.Error("Equal ignores the order of values")
+			}
+		})
+	}
+
+	t.Run("title_lang", func(t *testing.T) {
+		changed := base.Clone()
+		changed.SetLangTitle("en", "Other title")
+		if base.Equal(changed) {
+			t.Error("Equal ignores a changed tagged title")
+		}
+	})
+	t.Run("description_lang", func(t *testing.T) {
+		changed := base.Clone()
+		changed.SetLangDescription("es", "Descripción")
+		if base.Equal(changed) {
+			t.Error("Equal ignores an added tagged description")
+		}
+	})
+	t.Run("nil and empty", func(t *testing.T) {
+		if !(&DublinCore{Title: nil}).Equal(&DublinCore{Title: []string{}}) {
+			t.Error("a nil field isn't equal to an empty one")
+		}
+		if base.Equal(nil) {
+			t.Error("a record is equal to nil")
+		}
+	})
+}
+
+func TestFormatSet(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(dc *DublinCore)
+		want bool
+	}{
+		{"assigned directly", func(dc *DublinCore) { dc.Format = []string{"text/plain"} }, false},
+		{"other field", func(dc *DublinCore) { dc.Set("title", []string{"Analista"}) }, false},
+		{"SetFormat", func(dc *DublinCore) { dc.SetFormat("text/plain") }, true},
+		{"Set", func(dc *DublinCore) { dc.Set("format", []string{"text/plain"}) }, true},
+		{"SetAll", func(dc *DublinCore) { dc.SetAll(map[string][]string{"format": {"text/plain"}}) }, true},
+		{"Merge", func(dc *DublinCore) { dc.Merge(&DublinCore{Format: []string{"text/plain"}}, true) }, true},
+		{"Merge without format", func(dc *DublinCore) { dc.Merge(&DublinCore{Title: []string{"Analista"}}, true) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := New()
+			tt.set(dc)
+			if dc.FormatSet() != tt.want {
+				t.Errorf("FormatSet = %v, want %v", dc.FormatSet(), tt.want)
+			}
+			if dc.Clone().FormatSet() != tt.want {
+				t.Error("Clone doesn't keep FormatSet")
+			}
+		})
+	}
+}
+
+func TestTypeAndCategoryRoundTrip(t *testing.T) {
+	dc := &DublinCore{Type: []string{"Text"}, Category: []string{"curriculo"}}
+	data, err := dc.ToXML()
+	if err != nil {
+		t.Fatalf("ToXML: %v", err)
+	}
+	for _, element := range []string{"<type>Text</type>", "<category xmlns=\"http://schemas.openxmlformats.org/package/2006/metadata/core-properties\">curriculo</category>"} {
+		if !strings.Contains(string(data), element) {
+			t.Errorf("ToXML = %s, want it to contain %s", data, element)
+		}
+	}
+
+	parsed, err := FromXML(data)
+	if err != nil {
+		t.Fatalf("FromXML: %v", err)
+	}
+	if !slices.Equal(parsed.Type, dc.Type) || !slices.Equal(parsed.Category, dc.Category) {
+		t.Errorf("round trip gave Type %q and Category %q, want %q and %q", parsed.Type, parsed.Category, dc.Type, dc.Category)
+	}
+}
+
+func TestSetDate(t *testing.T) {
+	midnight := time.Date(2024, 1, 31, 0, 0, 0, 0, time.FixedZone("", 5*60*60))
+
+	dc := &DublinCore{}
+	dc.SetDate(midnight)
+	if want := []string{"2024-01-31T00:00:00+05:00"}; !slices.Equal(dc.Date, want) {
+		t.Errorf("SetDate at midnight = %q, want %q", dc.Date, want)
+	}
+	dc.SetDay(midnight)
+	if want := []string{"2024-01-31"}; !slices.Equal(dc.Date, want) {
+		t.Errorf("SetDay = %q, want %q", dc.Date, want)
+	}
+}
@@ -0,0 +1,58 @@
+package dublincore
+
+import "strings"
+
+// Namespaces of the elements described by Fields
+const (
+	ElementsNamespace       = "http://purl.org/dc/elements/1.1/"
+	CorePropertiesNamespace = "http://schemas.openxmlformats.org/package/2006/metadata/core-properties"
+)
+
+// FieldSpec describes a field of the model for building forms and other UIs
+type FieldSpec struct {
+	Name      string // name accepted by Get and Set, e.g. "title"
+	Element   string // prefixed element name in core.xml, e.g. "dc:title"
+	Namespace string // namespace URI of the element
+	Multi     bool   // holds a list of values (creators, keywords, ...) rather than one
+}
+
+// multiValued are the fields edited as lists; the others hold a single value
+// even though Dublin Core allows any element to repeat
+var multiValued = map[string]bool{
+	"creator":     true,
+	"subject":     true,
+	"publisher":   true,
+	"contributor": true,
+	"language":    true,
+	"keywords":    true,
+}
+
+// Fields describes every field accepted by Get and Set, in FieldNames order.
+// Keywords and category are Office core properties; the rest are DC elements.
+func Fields() []FieldSpec {
+	specs := make([]FieldSpec, 0, len(fieldNames))
+	for _, name := range fieldNames {
+		spec := FieldSpec{
+			Name:      name,
+			Element:   "dc:" + name,
+			Namespace: ElementsNamespace,
+			Multi:     multiValued[name],
+		}
+		if name == "keywords" || name == "category" {
+			spec.Element = "cp:" + name
+			spec.Namespace = CorePropertiesNamespace
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// FieldSpecFor returns the description of the named field, ignoring case
+func FieldSpecFor(name string) (FieldSpec, bool) {
+	for _, spec := range Fields() {
+		if spec.Name == strings.ToLower(strings.TrimSpace(name)) {
+			return spec, true
+		}
+	}
+	return FieldSpec{}, false
+}
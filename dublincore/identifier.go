@@ -0,0 +1,190 @@
+package dublincore
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Identifier schemes with a canonical form and validation
+const (
+	SchemeDOI  = "doi"
+	SchemeISBN = "isbn"
+	SchemeISSN = "issn"
+	SchemeURN  = "urn"
+	SchemeURI  = "uri"
+)
+
+var (
+	doiPattern  = regexp.MustCompile(`^10\.\d{4,9}/\S+$`)
+	urnPattern  = regexp.MustCompile(`(?i)^urn:[a-z0-9][a-z0-9-]{0,31}:\S+$`)
+	issnPattern = regexp.MustCompile(`^\d{4}-?\d{3}[\dXx]$`)
+)
+
+// doiPrefixes are the forms a DOI is commonly written in before the 10. prefix
+var doiPrefixes = []string{"https://doi.org/", "http://doi.org/", "https://dx.doi.org/", "http://dx.doi.org/", "doi:"}
+
+// AddIdentifier adds an identifier in the canonical form of its scheme:
+// "doi:10.1000/xyz", "urn:isbn:9780306406157", "urn:issn:0317-8471", or the
+// URN/URI itself. Invalid values for these schemes are an error; identifiers
+// of any other scheme are stored as "scheme:value".
+func (dc *DublinCore) AddIdentifier(scheme, value string) error {
+	identifier, err := CanonicalIdentifier(scheme, value)
+	if err != nil {
+		return err
+	}
+	for _, existing := range dc.Identifier {
+		if existing == identifier {
+			return nil
+		}
+	}
+	dc.Identifier = append(dc.Identifier, identifier)
+	return nil
+}
+
+// CanonicalIdentifier validates value for scheme and returns the form stored in dc:identifier
+func CanonicalIdentifier(scheme, value string) (string, error) {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("empty %s identifier", scheme)
+	}
+
+	switch scheme {
+	case SchemeDOI:
+		doi := trimDOI(value)
+		if !doiPattern.MatchString(doi) {
+			return "", fmt.Errorf("invalid DOI %q: expected 10.<registrant>/<suffix>", value)
+		}
+		return "doi:" + doi, nil
+	case SchemeISBN:
+		isbn := strings.ToUpper(stripSeparators(strings.TrimPrefix(strings.ToLower(value), "urn:isbn:")))
+		if !validISBN(isbn) {
+			return "", fmt.Errorf("invalid ISBN %q", value)
+		}
+		return "urn:isbn:" + isbn, nil
+	case SchemeISSN:
+		issn := strings.ToUpper(strings.TrimPrefix(strings.ToLower(value), "urn:issn:"))
+		if !issnPattern.MatchString(issn) || !validISSN(strings.ReplaceAll(issn, "-", "")) {
+			return "", fmt.Errorf("invalid ISSN %q", value)
+		}
+		digits := strings.ReplaceAll(issn, "-", "")
+		return "urn:issn:" + digits[:4] + "-" + digits[4:], nil
+	case SchemeURN:
+		// Accept the URN with or without its urn: prefix
+		if !strings.HasPrefix(strings.ToLower(value), "urn:") {
+			value = "urn:" + value
+		}
+		if !urnPattern.MatchString(value) {
+			return "", fmt.Errorf("invalid URN %q: expected urn:<namespace>:<id>", value)
+		}
+		// ISBN and ISSN URNs get the validation and canonical form of their scheme
+		nid, _, _ := strings.Cut(value[len("urn:"):], ":")
+		if nid = strings.ToLower(nid); nid == SchemeISBN || nid == SchemeISSN {
+			return CanonicalIdentifier(nid, value)
+		}
+		return "urn:" + value[len("urn:"):], nil
+	case SchemeURI:
+		if !isURI(value) {
+			return "", fmt.Errorf("invalid URI %q", value)
+		}
+		return value, nil
+	case "":
+		return "", fmt.Errorf("identifier scheme is empty")
+	}
+	return scheme + ":" + value, nil
+}
+
+// IdentifierFor returns the first identifier of scheme without its canonical
+// prefix, e.g. "10.1000/xyz" for SchemeDOI. DOIs written as doi.org links
+// and ISBN/ISSN URNs are recognised as well.
+func (dc *DublinCore) IdentifierFor(scheme string) (string, bool) {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	for _, identifier := range dc.Identifier {
+		lower := strings.ToLower(identifier)
+		switch scheme {
+		case SchemeDOI:
+			if doi := trimDOI(identifier); doiPattern.MatchString(doi) {
+				return doi, true
+			}
+		case SchemeISBN, SchemeISSN:
+			if prefix := "urn:" + scheme + ":"; strings.HasPrefix(lower, prefix) {
+				return identifier[len(prefix):], true
+			}
+		case SchemeURN:
+			if urnPattern.MatchString(identifier) {
+				return identifier, true
+			}
+		case SchemeURI:
+			if isURI(identifier) {
+				return identifier, true
+			}
+		default:
+			if strings.HasPrefix(lower, scheme+":") {
+				return identifier[len(scheme)+1:], true
+			}
+		}
+	}
+	return "", false
+}
+
+// trimDOI removes the doi: or doi.org prefix of a DOI
+func trimDOI(value string) string {
+	lower := strings.ToLower(value)
+	for _, prefix := range doiPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return value[len(prefix):]
+		}
+	}
+	return value
+}
+
+// stripSeparators removes the hyphens and spaces used to group ISBN digits
+func stripSeparators(value string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(value)
+}
+
+// validISBN checks the length and check digit of an ISBN-10 or ISBN-13
+func validISBN(isbn string) bool {
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, r := range isbn {
+			digit := int(r - '0')
+			if r == 'X' && i == 9 {
+				digit = 10
+			} else if r < '0' || r > '9' {
+				return false
+			}
+			sum += digit * (10 - i)
+		}
+		return sum%11 == 0
+	case 13:
+		sum := 0
+		for i, r := range isbn {
+			if r < '0' || r > '9' {
+				return false
+			}
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += int(r-'0') * weight
+		}
+		return sum%10 == 0
+	}
+	return false
+}
+
+// validISSN checks the check digit of an 8-character ISSN without hyphen
+func validISSN(issn string) bool {
+	sum := 0
+	for i, r := range issn[:7] {
+		sum += int(r-'0') * (8 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return issn[7] == 'X'
+	}
+	return int(issn[7]-'0') == check
+}
@@ -0,0 +1,50 @@
+package dublincore
+
+import "testing"
+
+func TestCanonicalIdentifier(t *testing.T) {
+	tests := []struct {
+		scheme, value string
+		want          string
+	}{
+		{"doi", "10.1000/xyz", "doi:10.1000/xyz"},
+		{"DOI", "https://doi.org/10.1000/xyz", "doi:10.1000/xyz"},
+		{"isbn", "978-0-306-40615-7", "urn:isbn:9780306406157"},
+		{"isbn", "0-306-40615-2", "urn:isbn:0306406152"},
+		{"issn", "03178471", "urn:issn:0317-8471"},
+		{"urn", "urn:isbn:978-0-306-40615-7", "urn:isbn:9780306406157"},
+		{"urn", "isbn:9780306406157", "urn:isbn:9780306406157"},
+		{"urn", "URN:ISSN:0317-8471", "urn:issn:0317-8471"},
+		{"urn", "urn:nbn:de:101-2024", "urn:nbn:de:101-2024"},
+		{"urn", "uuid:6e8bc430-9c3a-11d9-9669-0800200c9a66", "urn:uuid:6e8bc430-9c3a-11d9-9669-0800200c9a66"},
+		{"uri", "https://example.com/cv", "https://example.com/cv"},
+		{"orcid", "0000-0002-1825-0097", "orcid:0000-0002-1825-0097"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scheme+" "+tt.value, func(t *testing.T) {
+			got, err := CanonicalIdentifier(tt.scheme, tt.value)
+			if err != nil {
+				t.Fatalf("CanonicalIdentifier: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CanonicalIdentifier = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	invalid := []struct{ scheme, value string }{
+		{"doi", "11.1000/xyz"},
+		{"isbn", "978-0-306-40615-8"},
+		{"issn", "0317-8472"},
+		{"urn", "urn:isbn:9780306406158"},
+		{"urn", "no namespace"},
+		{"uri", "not a uri"},
+		{"", "value"},
+		{"doi", " "},
+	}
+	for _, tt := range invalid {
+		if got, err := CanonicalIdentifier(tt.scheme, tt.value); err == nil {
+			t.Errorf("CanonicalIdentifier(%q, %q) = %q, want an error", tt.scheme, tt.value, got)
+		}
+	}
+}
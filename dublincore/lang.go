@@ -0,0 +1,228 @@
+package dublincore
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// LangString is an element value tagged with the language it is written in
+type LangString struct {
+	Lang  string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty" json:"lang"`
+	Value string `xml:",chardata" json:"value"`
+}
+
+// WithLang returns plain values (untagged, in the default language) followed by tagged values
+func WithLang(plain []string, tagged []LangString) []LangString {
+	if len(plain) == 0 && len(tagged) == 0 {
+		return nil
+	}
+	values := make([]LangString, 0, len(plain)+len(tagged))
+	for _, value := range plain {
+		values = append(values, LangString{Value: value})
+	}
+	return append(values, tagged...)
+}
+
+// SplitLang separates untagged values from language-tagged ones, the inverse of WithLang
+func SplitLang(values []LangString) ([]string, []LangString) {
+	var plain []string
+	var tagged []LangString
+	for _, value := range values {
+		if value.Lang == "" {
+			plain = append(plain, value.Value)
+		} else {
+			tagged = append(tagged, value)
+		}
+	}
+	return plain, tagged
+}
+
+// LangValues returns the values of a field with their language tags: for
+// title and description the default values followed by the tagged ones, for
+// other fields the values untagged
+func (dc *DublinCore) LangValues(name string) ([]LangString, error) {
+	values, err := dc.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	var tagged []LangString
+	if field := dc.langField(name); field != nil {
+		tagged = *field
+	}
+	return WithLang(values, tagged), nil
+}
+
+// SetLangValues sets a field from language-tagged values, the inverse of
+// LangValues. Fields other than title and description keep only the values.
+func (dc *DublinCore) SetLangValues(name string, values []LangString) error {
+	field := dc.langField(name)
+	if field == nil {
+		plain := make([]string, len(values))
+		for i, value := range values {
+			plain[i] = value.Value
+		}
+		return dc.Set(name, plain)
+	}
+
+	plain, tagged := SplitLang(values)
+	if err := dc.Set(name, plain); err != nil {
+		return err
+	}
+	*field = tagged
+	return nil
+}
+
+// SetLangTitle sets the title for a language, replacing any existing title in that language.
+// An empty lang sets the default title.
+func (dc *DublinCore) SetLangTitle(lang, title string) {
+	if lang == "" {
+		dc.SetTitle(title)
+		return
+	}
+	dc.TitleLang = setLang(dc.TitleLang, lang, title)
+}
+
+// TitleFor returns the title in lang, falling back to the default title
+func (dc *DublinCore) TitleFor(lang string) string {
+	return lookupLang(dc.Title, dc.TitleLang, lang)
+}
+
+// SetLangDescription sets the description for a language, replacing any existing description
+// in that language. An empty lang sets the default description.
+func (dc *DublinCore) SetLangDescription(lang, description string) {
+	if lang == "" {
+		dc.SetDescription(description)
+		return
+	}
+	dc.DescriptionLang = setLang(dc.DescriptionLang, lang, description)
+}
+
+// DescriptionFor returns the description in lang, falling back to the default description
+func (dc *DublinCore) DescriptionFor(lang string) string {
+	return lookupLang(dc.Description, dc.DescriptionLang, lang)
+}
+
+// mergeLang copies the language-tagged values of other, replacing values in the
+// same language only with overwrite
+func (dc *DublinCore) mergeLang(other *DublinCore, overwrite bool) {
+	for _, name := range []string{"title", "description"} {
+		tagged := dc.langField(name)
+		for _, value := range *other.langField(name) {
+			if overwrite || !hasLang(*tagged, value.Lang) {
+				*tagged = setLang(*tagged, value.Lang, value.Value)
+			}
+		}
+	}
+}
+
+// hasLang reports whether values contains a value tagged with lang
+func hasLang(values []LangString, lang string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value.Lang, lang) {
+			return true
+		}
+	}
+	return false
+}
+
+// setLang replaces the value tagged with lang or appends a new one
+func setLang(values []LangString, lang, value string) []LangString {
+	for i := range values {
+		if strings.EqualFold(values[i].Lang, lang) {
+			values[i].Value = value
+			return values
+		}
+	}
+	return append(values, LangString{Lang: lang, Value: value})
+}
+
+// lookupLang finds the value tagged with lang, or the first plain value
+func lookupLang(plain []string, tagged []LangString, lang string) string {
+	for _, value := range tagged {
+		if strings.EqualFold(value.Lang, lang) {
+			return value.Value
+		}
+	}
+	if len(plain) > 0 {
+		return plain[0]
+	}
+	return ""
+}
+
+// dublinCoreXML mirrors DublinCore with Title and Description holding their language tags
+type dublinCoreXML struct {
+	XMLName     xml.Name     `xml:"http://purl.org/dc/elements/1.1/ dc"`
+	Title       []LangString `xml:"title,omitempty"`
+	Creator     []string     `xml:"creator,omitempty"`
+	Subject     []string     `xml:"subject,omitempty"`
+	Description []LangString `xml:"description,omitempty"`
+	Publisher   []string     `xml:"publisher,omitempty"`
+	Contributor []string     `xml:"contributor,omitempty"`
+	Date        []string     `xml:"date,omitempty"`
+	Type        []string     `xml:"type,omitempty"`
+	Format      []string     `xml:"format,omitempty"`
+	Identifier  []string     `xml:"identifier,omitempty"`
+	Source      []string     `xml:"source,omitempty"`
+	Language    []string     `xml:"language,omitempty"`
+	Relation    []string     `xml:"relation,omitempty"`
+	Coverage    []string     `xml:"coverage,omitempty"`
+	Rights      []string     `xml:"rights,omitempty"`
+	Keywords    []string     `xml:"http://schemas.openxmlformats.org/package/2006/metadata/core-properties keywords,omitempty"`
+	Category    []string     `xml:"http://schemas.openxmlformats.org/package/2006/metadata/core-properties category,omitempty"`
+}
+
+// MarshalXML writes language-tagged titles and descriptions with an xml:lang attribute
+func (dc DublinCore) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "DublinCore" {
+		// Marshalers get the type name by default instead of the XMLName tag
+		start.Name = xml.Name{Space: dcNamespace, Local: "dc"}
+	}
+	return e.EncodeElement(dublinCoreXML{
+		Title:       WithLang(dc.Title, dc.TitleLang),
+		Creator:     dc.Creator,
+		Subject:     dc.Subject,
+		Description: WithLang(dc.Description, dc.DescriptionLang),
+		Publisher:   dc.Publisher,
+		Contributor: dc.Contributor,
+		Date:        dc.Date,
+		Type:        dc.Type,
+		Format:      dc.Format,
+		Identifier:  dc.Identifier,
+		Source:      dc.Source,
+		Language:    dc.Language,
+		Relation:    dc.Relation,
+		Coverage:    dc.Coverage,
+		Rights:      dc.Rights,
+		Keywords:    dc.Keywords,
+		Category:    dc.Category,
+	}, start)
+}
+
+// UnmarshalXML reads titles and descriptions, keeping xml:lang tagged values apart
+func (dc *DublinCore) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var aux dublinCoreXML
+	if err := d.DecodeElement(&aux, &start); err != nil {
+		return err
+	}
+	*dc = DublinCore{
+		XMLName:     aux.XMLName,
+		Creator:     aux.Creator,
+		Subject:     aux.Subject,
+		Publisher:   aux.Publisher,
+		Contributor: aux.Contributor,
+		Date:        aux.Date,
+		Type:        aux.Type,
+		Format:      aux.Format,
+		Identifier:  aux.Identifier,
+		Source:      aux.Source,
+		Language:    aux.Language,
+		Relation:    aux.Relation,
+		Coverage:    aux.Coverage,
+		Rights:      aux.Rights,
+		Keywords:    aux.Keywords,
+		Category:    aux.Category,
+	}
+	dc.Title, dc.TitleLang = SplitLang(aux.Title)
+	dc.Description, dc.DescriptionLang = SplitLang(aux.Description)
+	return nil
+}
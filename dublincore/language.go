@@ -0,0 +1,126 @@
+package dublincore
+
+import "strings"
+
+// languageNames maps common ISO 639-1 codes to their English names
+var languageNames = map[string]string{
+	"af": "Afrikaans",
+	"ar": "Arabic",
+	"bg": "Bulgarian",
+	"bn": "Bengali",
+	"ca": "Catalan",
+	"cs": "Czech",
+	"da": "Danish",
+	"de": "German",
+	"el": "Greek",
+	"en": "English",
+	"eo": "Esperanto",
+	"es": "Spanish",
+	"et": "Estonian",
+	"eu": "Basque",
+	"fa": "Persian",
+	"fi": "Finnish",
+	"fr": "French",
+	"ga": "Irish",
+	"gl": "Galician",
+	"gn": "Guarani",
+	"he": "Hebrew",
+	"hi": "Hindi",
+	"hr": "Croatian",
+	"hu": "Hungarian",
+	"id": "Indonesian",
+	"is": "Icelandic",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"la": "Latin",
+	"lt": "Lithuanian",
+	"lv": "Latvian",
+	"ms": "Malay",
+	"nb": "Norwegian Bokmål",
+	"nl": "Dutch",
+	"nn": "Norwegian Nynorsk",
+	"no": "Norwegian",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ro": "Romanian",
+	"ru": "Russian",
+	"sk": "Slovak",
+	"sl": "Slovenian",
+	"sq": "Albanian",
+	"sr": "Serbian",
+	"sv": "Swedish",
+	"sw": "Swahili",
+	"ta": "Tamil",
+	"th": "Thai",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"ur": "Urdu",
+	"vi": "Vietnamese",
+	"zh": "Chinese",
+}
+
+// regionNames maps common ISO 3166-1 and UN M.49 region codes to their English names
+var regionNames = map[string]string{
+	"419": "Latin America",
+	"AO":  "Angola",
+	"AR":  "Argentina",
+	"AT":  "Austria",
+	"AU":  "Australia",
+	"BE":  "Belgium",
+	"BO":  "Bolivia",
+	"BR":  "Brazil",
+	"CA":  "Canada",
+	"CH":  "Switzerland",
+	"CL":  "Chile",
+	"CN":  "China",
+	"CO":  "Colombia",
+	"DE":  "Germany",
+	"ES":  "Spain",
+	"FR":  "France",
+	"GB":  "United Kingdom",
+	"HK":  "Hong Kong",
+	"IE":  "Ireland",
+	"IN":  "India",
+	"IT":  "Italy",
+	"JP":  "Japan",
+	"KR":  "South Korea",
+	"MO":  "Macao",
+	"MX":  "Mexico",
+	"MZ":  "Mozambique",
+	"NL":  "Netherlands",
+	"NZ":  "New Zealand",
+	"PE":  "Peru",
+	"PT":  "Portugal",
+	"PY":  "Paraguay",
+	"TW":  "Taiwan",
+	"US":  "United States",
+	"UY":  "Uruguay",
+	"VE":  "Venezuela",
+	"ZA":  "South Africa",
+}
+
+// LanguageName returns the English name of a language code such as "pt" or
+// "en-US" ("English (United States)"). Unknown regions keep their code; an
+// unknown language returns the code unchanged and false.
+func LanguageName(code string) (string, bool) {
+	code = strings.TrimSpace(code)
+	subtags := strings.Split(strings.ReplaceAll(code, "_", "-"), "-")
+
+	name, ok := languageNames[strings.ToLower(subtags[0])]
+	if !ok {
+		return code, false
+	}
+
+	// The region is the first two-letter or three-digit subtag after the language
+	for _, subtag := range subtags[1:] {
+		if len(subtag) == 2 || (len(subtag) == 3 && subtag[0] >= '0' && subtag[0] <= '9') {
+			region := strings.ToUpper(subtag)
+			if regionName, ok := regionNames[region]; ok {
+				region = regionName
+			}
+			return name + " (" + region + ")", true
+		}
+	}
+	return name, true
+}
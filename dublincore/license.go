@@ -0,0 +1,41 @@
+package dublincore
+
+import "strings"
+
+// license is the rights statement and reference URL for a license identifier
+type license struct {
+	Statement string
+	URL       string
+}
+
+// licenses maps SPDX identifiers (and "all-rights-reserved") to their rights statement
+var licenses = map[string]license{
+	"cc-by-4.0":           {"Creative Commons Attribution 4.0 International (CC BY 4.0)", "https://creativecommons.org/licenses/by/4.0/"},
+	"cc-by-sa-4.0":        {"Creative Commons Attribution-ShareAlike 4.0 International (CC BY-SA 4.0)", "https://creativecommons.org/licenses/by-sa/4.0/"},
+	"cc-by-nc-4.0":        {"Creative Commons Attribution-NonCommercial 4.0 International (CC BY-NC 4.0)", "https://creativecommons.org/licenses/by-nc/4.0/"},
+	"cc-by-nd-4.0":        {"Creative Commons Attribution-NoDerivatives 4.0 International (CC BY-ND 4.0)", "https://creativecommons.org/licenses/by-nd/4.0/"},
+	"cc-by-nc-sa-4.0":     {"Creative Commons Attribution-NonCommercial-ShareAlike 4.0 International (CC BY-NC-SA 4.0)", "https://creativecommons.org/licenses/by-nc-sa/4.0/"},
+	"cc-by-nc-nd-4.0":     {"Creative Commons Attribution-NonCommercial-NoDerivatives 4.0 International (CC BY-NC-ND 4.0)", "https://creativecommons.org/licenses/by-nc-nd/4.0/"},
+	"cc0-1.0":             {"Creative Commons Zero v1.0 Universal (CC0 1.0)", "https://creativecommons.org/publicdomain/zero/1.0/"},
+	"mit":                 {"MIT License", "https://opensource.org/licenses/MIT"},
+	"apache-2.0":          {"Apache License 2.0", "https://www.apache.org/licenses/LICENSE-2.0"},
+	"all-rights-reserved": {"All rights reserved", ""},
+}
+
+// SetLicense sets Rights to the statement and URL of a known license
+// identifier such as "CC-BY-4.0", "MIT" or "all-rights-reserved" (case-insensitive).
+// Unknown identifiers are stored verbatim and SetLicense returns false.
+func (dc *DublinCore) SetLicense(id string) bool {
+	id = strings.TrimSpace(id)
+	l, ok := licenses[strings.ToLower(id)]
+	if !ok {
+		dc.Rights = []string{id}
+		return false
+	}
+
+	dc.Rights = []string{l.Statement}
+	if l.URL != "" {
+		dc.Rights = append(dc.Rights, l.URL)
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package dublincore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultRenderFields are the fields Render shows when no others are requested
+var DefaultRenderFields = []string{"title", "creator", "keywords", "description", "category"}
+
+// fieldLabels holds the display label and emoji of each field
+var fieldLabels = map[string]struct{ label, emoji string }{
+	"title":       {"Title", "📝"},
+	"creator":     {"Creator(s)", "👤"},
+	"subject":     {"Subject", "🏷️"},
+	"description": {"Description", "📋"},
+	"publisher":   {"Publisher", "🏢"},
+	"contributor": {"Contributor(s)", "🤝"},
+	"date":        {"Date", "📅"},
+	"type":        {"Type", "🗂️"},
+	"format":      {"Format", "💾"},
+	"identifier":  {"Identifier", "🆔"},
+	"source":      {"Source", "🔗"},
+	"language":    {"Language", "🌐"},
+	"relation":    {"Relation", "📎"},
+	"coverage":    {"Coverage", "🗺️"},
+	"rights":      {"Rights", "⚖️"},
+	"keywords":    {"Keywords", "🔑"},
+	"category":    {"Category", "📂"},
+}
+
+// RenderOptions controls the output of Render
+type RenderOptions struct {
+	// Fields lists the fields to show, in order; nil uses DefaultRenderFields
+	Fields []string
+	// NoEmoji drops the emoji in front of each label
+	NoEmoji bool
+	// Placeholder is shown for empty fields, e.g. "(none)"
+	Placeholder string
+	// ResolveLanguages shows known language codes by name, e.g. "en-US" as
+	// "English (United States)"; the metadata keeps the codes
+	ResolveLanguages bool
+}
+
+// Render formats the metadata as aligned "Label: value" lines, joining
+// multiple values with ", ". Unknown field names are an error.
+func Render(dc *DublinCore, opts RenderOptions) (string, error) {
+	fields := opts.Fields
+	if fields == nil {
+		fields = DefaultRenderFields
+	}
+
+	width := 0
+	for _, name := range fields {
+		label, ok := fieldLabels[strings.ToLower(name)]
+		if !ok {
+			return "", fmt.Errorf("unknown field: %s", name)
+		}
+		width = max(width, len(label.label)+1)
+	}
+
+	var b strings.Builder
+	for _, name := range fields {
+		label := fieldLabels[strings.ToLower(name)]
+		values, _ := dc.Get(name)
+		if opts.ResolveLanguages && strings.EqualFold(name, "language") {
+			values = languageDisplayNames(values)
+		}
+
+		value := strings.Join(values, ", ")
+		if strings.TrimSpace(value) == "" {
+			value = opts.Placeholder
+		}
+
+		if !opts.NoEmoji {
+			b.WriteString(label.emoji + " ")
+		}
+		fmt.Fprintf(&b, "%-*s %s\n", width, label.label+":", value)
+	}
+	return b.String(), nil
+}
+
+// languageDisplayNames returns a copy of codes with known languages replaced by their names
+func languageDisplayNames(codes []string) []string {
+	names := make([]string, len(codes))
+	for i, code := range codes {
+		names[i], _ = LanguageName(code)
+	}
+	return names
+}
@@ -0,0 +1,122 @@
+package dublincore
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// TextError describes a field value that can't be written to XML as is
+type TextError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *TextError) Error() string {
+	return fmt.Sprintf("%s: value %q %s", e.Field, e.Value, e.Reason)
+}
+
+// allowedInXML reports whether r may appear in XML 1.0 text. DEL and the C1
+// controls are allowed by the specification but discouraged, and in pasted
+// text they are almost always mis-decoded bytes, so they are refused too.
+func allowedInXML(r rune) bool {
+	switch {
+	case r == '\t', r == '\n', r == '\r':
+		return true
+	case r < 0x20, r >= 0x7F && r <= 0x9F:
+		return false
+	case r >= 0xD800 && r <= 0xDFFF, r == 0xFFFE, r == 0xFFFF:
+		return false
+	}
+	return r <= utf8.MaxRune
+}
+
+// SanitizeValue returns value with invalid UTF-8 sequences replaced by U+FFFD
+// and the control characters XML can't carry removed
+func SanitizeValue(value string) string {
+	value = strings.ToValidUTF8(value, string(utf8.RuneError))
+	return strings.Map(func(r rune) rune {
+		if allowedInXML(r) {
+			return r
+		}
+		return -1
+	}, value)
+}
+
+// checkText returns why value can't be written to XML as is, or "" if it can
+func checkText(value string) string {
+	if !utf8.ValidString(value) {
+		return "is not valid UTF-8"
+	}
+	if strings.IndexFunc(value, func(r rune) bool { return !allowedInXML(r) }) >= 0 {
+		return "contains control characters not allowed in XML"
+	}
+	return ""
+}
+
+// CheckText returns one error per value, including language-tagged titles
+// and descriptions, that SanitizeValue would change
+func (dc *DublinCore) CheckText() []error {
+	var errs []error
+	for _, name := range fieldNames {
+		for _, value := range *dc.field(name) {
+			if reason := checkText(value); reason != "" {
+				errs = append(errs, &TextError{Field: name, Value: value, Reason: reason})
+			}
+		}
+	}
+	for _, tagged := range []struct {
+		field  string
+		values []LangString
+	}{{"title", dc.TitleLang}, {"description", dc.DescriptionLang}} {
+		for _, value := range tagged.values {
+			if reason := checkText(value.Value); reason != "" {
+				errs = append(errs, &TextError{Field: tagged.field, Value: value.Value, Reason: reason})
+			}
+		}
+	}
+	return errs
+}
+
+// Sanitize applies SanitizeValue to every value of dc and returns the names
+// of the fields it changed
+func (dc *DublinCore) Sanitize() []string {
+	var changed []string
+	mark := func(name string) {
+		if !slices.Contains(changed, name) {
+			changed = append(changed, name)
+		}
+	}
+
+	for _, name := range fieldNames {
+		values := *dc.field(name)
+		for i, value := range values {
+			if clean := SanitizeValue(value); clean != value {
+				values[i] = clean
+				mark(name)
+			}
+		}
+	}
+	for i, value := range dc.TitleLang {
+		if clean := SanitizeValue(value.Value); clean != value.Value {
+			dc.TitleLang[i].Value = clean
+			mark("title")
+		}
+	}
+	for i, value := range dc.DescriptionLang {
+		if clean := SanitizeValue(value.Value); clean != value.Value {
+			dc.DescriptionLang[i].Value = clean
+			mark("description")
+		}
+	}
+	return changed
+}
+
+// Sanitized returns a sanitized copy of dc, leaving dc untouched
+func (dc *DublinCore) Sanitized() *DublinCore {
+	clone := dc.Clone()
+	clone.Sanitize()
+	return clone
+}
@@ -0,0 +1,114 @@
+package dublincore
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// badText pairs byte sequences pasted input can contain with their sanitized form
+var badText = []struct {
+	name  string
+	value string
+	want  string
+}{
+	{"lone continuation byte", "Ana\x80lista", "Ana\ufffdlista"},
+	{"invalid start byte", "\xffAnalista", "\ufffdAnalista"},
+	{"truncated sequence", "Analista \xe2\x82", "Analista \ufffd"},
+	{"overlong encoding", "Analista\xc0\xaf", "Analista\ufffd"},
+	{"encoded surrogate", "Analista\xed\xa0\x80", "Analista\ufffd"},
+	{"NUL", "Ana\x00lista", "Analista"},
+	{"vertical tab", "Ana\vlista", "Analista"},
+	{"form feed", "Analista\f", "Analista"},
+	{"escape", "\x1b[1mAnalista", "[1mAnalista"},
+	{"DEL", "Ana\x7flista", "Analista"},
+	{"C1 control", "Ana\u0085lista", "Analista"},
+	{"noncharacters", "Analista\ufffe\uffff", "Analista"},
+}
+
+func TestSanitizeValue(t *testing.T) {
+	for _, tt := range badText {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeValue(tt.value); got != tt.want {
+				t.Errorf("SanitizeValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"Analista", "Currículo\tGo\r\nSão Paulo", "日本語 🚀", "\ufffd"} {
+		if got := SanitizeValue(value); got != value {
+			t.Errorf("SanitizeValue(%q) = %q, want it unchanged", value, got)
+		}
+	}
+}
+
+func TestCheckText(t *testing.T) {
+	for _, tt := range badText {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := &DublinCore{Title: []string{"Analista"}, Creator: []string{tt.value}}
+			errs := dc.CheckText()
+			if len(errs) != 1 {
+				t.Fatalf("CheckText = %v, want one error", errs)
+			}
+			var textErr *TextError
+			if !errors.As(errs[0], &textErr) || textErr.Field != "creator" || textErr.Value != tt.value {
+				t.Errorf("CheckText = %v, want a TextError for creator", errs[0])
+			}
+		})
+	}
+
+	t.Run("language-tagged", func(t *testing.T) {
+		dc := &DublinCore{}
+		dc.SetLangTitle("en", "Analyst\x00")
+		dc.SetLangDescription("es", "Descripci\xf3n")
+		var fields []string
+		for _, err := range dc.CheckText() {
+			var textErr *TextError
+			if errors.As(err, &textErr) {
+				fields = append(fields, textErr.Field)
+			}
+		}
+		if want := []string{"title", "description"}; !slices.Equal(fields, want) {
+			t.Errorf("CheckText reported %q, want %q", fields, want)
+		}
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		dc := &DublinCore{Title: []string{"Currículo"}, Description: []string{"linha 1\nlinha 2"}}
+		if errs := dc.CheckText(); len(errs) > 0 {
+			t.Errorf("CheckText = %v, want none", errs)
+		}
+	})
+}
+
+func TestSanitize(t *testing.T) {
+	dc := &DublinCore{
+		Title:    []string{"Analista"},
+		Creator:  []string{"Ana", "Bruno\x00"},
+		Keywords: []string{"Go\xff"},
+	}
+	dc.SetLangDescription("en", "Backend\vdeveloper")
+	original := dc.Clone()
+
+	clean := dc.Sanitized()
+	if !dc.Equal(original) {
+		t.Error("Sanitized changed the original")
+	}
+	if errs := clean.CheckText(); len(errs) > 0 {
+		t.Errorf("CheckText after Sanitized = %v, want none", errs)
+	}
+
+	changed := dc.Sanitize()
+	if want := []string{"creator", "keywords", "description"}; !slices.Equal(changed, want) {
+		t.Errorf("Sanitize changed %q, want %q", changed, want)
+	}
+	if !dc.Equal(clean) {
+		t.Errorf("Sanitize and Sanitized differ: %+v", Diff(dc, clean))
+	}
+	if !slices.Equal(dc.Creator, []string{"Ana", "Bruno"}) || dc.DescriptionFor("en") != "Backenddeveloper" {
+		t.Errorf("Creator = %q, description = %q after Sanitize", dc.Creator, dc.DescriptionFor("en"))
+	}
+	if changed := dc.Sanitize(); len(changed) > 0 {
+		t.Errorf("a second Sanitize changed %q, want nothing", changed)
+	}
+}
@@ -0,0 +1,63 @@
+package dublincore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SyncDirection selects which way SyncKeywordsAndSubjects copies terms
+type SyncDirection int
+
+const (
+	// SyncNone leaves Keywords and Subject as they are
+	SyncNone SyncDirection = iota
+	// KeywordsToSubjects adds every keyword missing from Subject
+	KeywordsToSubjects
+	// SubjectsToKeywords adds every subject missing from Keywords
+	SubjectsToKeywords
+	// SyncBoth makes both fields hold the union of their terms
+	SyncBoth
+)
+
+// syncDirectionNames are the names accepted by ParseSyncDirection
+var syncDirectionNames = map[string]SyncDirection{
+	"none":                 SyncNone,
+	"keywords-to-subjects": KeywordsToSubjects,
+	"subjects-to-keywords": SubjectsToKeywords,
+	"both":                 SyncBoth,
+}
+
+// ParseSyncDirection parses "none", "keywords-to-subjects", "subjects-to-keywords" or "both"
+func ParseSyncDirection(name string) (SyncDirection, error) {
+	direction, ok := syncDirectionNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return SyncNone, fmt.Errorf("unknown sync direction %q (expected keywords-to-subjects, subjects-to-keywords, both or none)", name)
+	}
+	return direction, nil
+}
+
+// SyncKeywordsAndSubjects mirrors terms between cp:keywords and dc:subject so
+// readers of either field see them. Copying only adds: a term is appended,
+// trimmed, to the target field when no value there matches it ignoring case,
+// and nothing is ever removed or reordered.
+func (dc *DublinCore) SyncKeywordsAndSubjects(direction SyncDirection) {
+	keywords, subjects := dc.Keywords, dc.Subject
+
+	if direction == KeywordsToSubjects || direction == SyncBoth {
+		dc.Subject = appendMissingFold(dc.Subject, keywords)
+	}
+	if direction == SubjectsToKeywords || direction == SyncBoth {
+		dc.Keywords = appendMissingFold(dc.Keywords, subjects)
+	}
+}
+
+// appendMissingFold appends the non-empty values not yet in target, ignoring case
+func appendMissingFold(target, values []string) []string {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !containsFold(target, value) {
+			target = append(target, value)
+		}
+	}
+	return target
+}
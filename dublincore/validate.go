@@ -0,0 +1,89 @@
+package dublincore
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// w3cdtfLayouts are the date formats allowed by the W3C Date and Time Formats note
+var w3cdtfLayouts = []string{
+	"2006",
+	"2006-01",
+	"2006-01-02",
+	"2006-01-02T15:04Z07:00",
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
+// languagePattern matches ISO 639 codes optionally followed by BCP-47 subtags (e.g. "en", "pt-BR")
+var languagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+
+// ValidationError describes a single field value that violates a Dublin Core constraint
+type ValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: invalid value %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// Validate checks the field constraints and returns one error per offending value,
+// including the values CheckText rejects. All elements are optional, so empty
+// fields are always valid.
+func (dc *DublinCore) Validate() []error {
+	var errs []error
+
+	for _, date := range dc.Date {
+		if !IsW3CDTF(date) {
+			errs = append(errs, &ValidationError{Field: "date", Value: date, Reason: "not a W3CDTF date"})
+		}
+	}
+
+	for _, lang := range dc.Language {
+		if !languagePattern.MatchString(lang) {
+			errs = append(errs, &ValidationError{Field: "language", Value: lang, Reason: "not a BCP-47/ISO 639 language code"})
+		}
+	}
+
+	for _, id := range dc.Identifier {
+		if !isURI(id) {
+			errs = append(errs, &ValidationError{Field: "identifier", Value: id, Reason: "not a well-formed URI"})
+		}
+	}
+
+	return append(errs, dc.CheckText()...)
+}
+
+// IsW3CDTF reports whether value is a date in one of the W3CDTF profiles of ISO 8601
+func IsW3CDTF(value string) bool {
+	_, ok := ParseW3CDTF(value)
+	return ok
+}
+
+// ParseW3CDTF parses a date in one of the W3CDTF profiles; partial dates
+// such as "2024-01" resolve to the start of the period in UTC
+func ParseW3CDTF(value string) (time.Time, bool) {
+	for _, layout := range w3cdtfLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isURI reports whether value is an absolute URI such as "https://..." or "urn:isbn:..."
+func isURI(value string) bool {
+	if strings.ContainsAny(value, " \t\r\n") {
+		return false
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	return u.Opaque != "" || u.Host != "" || u.Path != ""
+}
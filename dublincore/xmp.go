@@ -0,0 +1,231 @@
+package dublincore
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	dcNamespace  = "http://purl.org/dc/elements/1.1/"
+	rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+)
+
+// xmpContainers is the RDF container the XMP specification uses for each
+// repeatable Dublin Core element. Elements not listed are simple text values.
+var xmpContainers = map[string]string{
+	"title":       "rdf:Alt",
+	"description": "rdf:Alt",
+	"rights":      "rdf:Alt",
+	"creator":     "rdf:Seq",
+	"date":        "rdf:Seq",
+	"subject":     "rdf:Bag",
+	"publisher":   "rdf:Bag",
+	"contributor": "rdf:Bag",
+	"type":        "rdf:Bag",
+	"language":    "rdf:Bag",
+	"relation":    "rdf:Bag",
+}
+
+// xmpDescription is an rdf:Description holding dc: properties
+type xmpDescription struct {
+	Attrs      []xml.Attr    `xml:",any,attr"`
+	Properties []xmpProperty `xml:",any"`
+}
+
+// xmpProperty is a property written either as text or as an RDF container
+type xmpProperty struct {
+	XMLName xml.Name
+	Text    string       `xml:",chardata"`
+	Seq     []string     `xml:"Seq>li"`
+	Bag     []string     `xml:"Bag>li"`
+	Alt     []LangString `xml:"Alt>li"`
+}
+
+// ToXMP serializes the Dublin Core elements into an XMP packet, the form
+// used to embed metadata in PDFs and images. Keywords and Category are not
+// Dublin Core elements and are left out.
+func (dc *DublinCore) ToXMP() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("<?xpacket begin=\"\uFEFF\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+
+	start := func(name string, attrs ...xml.Attr) error {
+		return enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+	}
+	end := func(name string) error {
+		return enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}})
+	}
+	attr := func(name, value string) xml.Attr {
+		return xml.Attr{Name: xml.Name{Local: name}, Value: value}
+	}
+	text := func(name, value string, attrs ...xml.Attr) error {
+		if err := start(name, attrs...); err != nil {
+			return err
+		}
+		if err := enc.EncodeToken(xml.CharData(value)); err != nil {
+			return err
+		}
+		return end(name)
+	}
+
+	if err := start("x:xmpmeta", attr("xmlns:x", "adobe:ns:meta/")); err != nil {
+		return nil, err
+	}
+	if err := start("rdf:RDF", attr("xmlns:rdf", rdfNamespace)); err != nil {
+		return nil, err
+	}
+	if err := start("rdf:Description", attr("rdf:about", ""), attr("xmlns:dc", dcNamespace)); err != nil {
+		return nil, err
+	}
+
+	for _, name := range fieldNames {
+		values := *dc.field(name)
+		var tagged []LangString
+		if field := dc.langField(name); field != nil {
+			tagged = *field
+		}
+		if !isDCElement(name) || len(values)+len(tagged) == 0 {
+			continue
+		}
+
+		property := "dc:" + name
+		container, ok := xmpContainers[name]
+		if !ok && len(values) == 1 {
+			if err := text(property, values[0]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !ok {
+			container = "rdf:Bag"
+		}
+
+		if err := start(property); err != nil {
+			return nil, err
+		}
+		if err := start(container); err != nil {
+			return nil, err
+		}
+		for i, value := range values {
+			var attrs []xml.Attr
+			if container == "rdf:Alt" && i == 0 {
+				attrs = append(attrs, attr("xml:lang", "x-default"))
+			}
+			if err := text("rdf:li", value, attrs...); err != nil {
+				return nil, err
+			}
+		}
+		for _, value := range tagged {
+			if err := text("rdf:li", value.Value, attr("xml:lang", value.Lang)); err != nil {
+				return nil, err
+			}
+		}
+		if err := end(container); err != nil {
+			return nil, err
+		}
+		if err := end(property); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range []string{"rdf:Description", "rdf:RDF", "x:xmpmeta"} {
+		if err := end(name); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("\n" + `<?xpacket end="w"?>`)
+	return buf.Bytes(), nil
+}
+
+// FromXMP parses the dc: properties of every rdf:Description in an XMP packet
+func FromXMP(data []byte) (*DublinCore, error) {
+	dc := &DublinCore{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	found := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("XMP parsing failed: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Space != rdfNamespace || start.Name.Local != "Description" {
+			continue
+		}
+		found = true
+
+		var desc xmpDescription
+		if err := decoder.DecodeElement(&desc, &start); err != nil {
+			return nil, fmt.Errorf("XMP parsing failed: %w", err)
+		}
+		dc.addXMPDescription(desc)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("XMP parsing failed: no rdf:Description found")
+	}
+	return dc, nil
+}
+
+// addXMPDescription appends the dc: properties of one rdf:Description
+func (dc *DublinCore) addXMPDescription(desc xmpDescription) {
+	// Simple properties may be written as attributes: <rdf:Description dc:format="...">
+	for _, attr := range desc.Attrs {
+		if attr.Name.Space == dcNamespace && isDCElement(attr.Name.Local) {
+			field := dc.field(attr.Name.Local)
+			*field = append(*field, attr.Value)
+		}
+	}
+
+	for _, property := range desc.Properties {
+		if property.XMLName.Space != dcNamespace || !isDCElement(property.XMLName.Local) {
+			continue
+		}
+		field := dc.field(property.XMLName.Local)
+
+		items := append(append([]string{}, property.Seq...), property.Bag...)
+		for _, item := range property.Alt {
+			// x-default is the document's default language
+			if tagged := dc.langField(property.XMLName.Local); tagged != nil && item.Lang != "" && item.Lang != "x-default" {
+				*tagged = append(*tagged, item)
+				continue
+			}
+			items = append(items, item.Value)
+		}
+		if len(items) == 0 {
+			if value := strings.TrimSpace(property.Text); value != "" {
+				items = []string{value}
+			}
+		}
+		*field = append(*field, items...)
+	}
+}
+
+// langField returns the language-tagged values of name, or nil if the element has none
+func (dc *DublinCore) langField(name string) *[]LangString {
+	switch name {
+	case "title":
+		return &dc.TitleLang
+	case "description":
+		return &dc.DescriptionLang
+	}
+	return nil
+}
+
+// isDCElement reports whether name is one of the fifteen Dublin Core elements
+func isDCElement(name string) bool {
+	return name != "keywords" && name != "category" && (&DublinCore{}).field(name) != nil
+}
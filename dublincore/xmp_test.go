@@ -0,0 +1,49 @@
+package dublincore
+
+import (
+	"testing"
+)
+
+func FuzzFromXMP(f *testing.F) {
+	dc := &DublinCore{
+		Title:       []string{"Analista Backend"},
+		Creator:     []string{"Eduardo Moro", "Ana"},
+		Subject:     []string{"Go", "PHP"},
+		Description: []string{"Backend developer"},
+		Date:        []string{"2024-01-02"},
+		Format:      []string{"application/pdf"},
+		Rights:      []string{"CC-BY-4.0"},
+	}
+	dc.SetLangTitle("en", "Backend Analyst")
+	packet, err := dc.ToXMP()
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(packet)
+	f.Add(packet[:len(packet)/2]) // truncated
+	f.Add([]byte(`<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` +
+		`<rdf:Description xmlns:dc="http://purl.org/dc/elements/1.1/" dc:format="image/jpeg"><dc:source>scan</dc:source></rdf:Description>` +
+		`</rdf:RDF></x:xmpmeta>`))
+	f.Add([]byte(`<rdf:Description xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"/>`))
+	f.Add([]byte(`<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		dc, err := FromXMP(data)
+		if err != nil {
+			return
+		}
+
+		// Whatever parses must be written back as a packet that parses to the same record
+		out, err := dc.ToXMP()
+		if err != nil {
+			t.Fatalf("parsed XMP can't be written: %v", err)
+		}
+		reparsed, err := FromXMP(out)
+		if err != nil {
+			t.Fatalf("written XMP doesn't parse: %v\n%s", err, out)
+		}
+		if !reparsed.Equal(dc) {
+			t.Errorf("XMP round trip changed the record: %+v\n%s", Diff(dc, reparsed), out)
+		}
+	})
+}
@@ -0,0 +1,354 @@
+// Package epub reads and writes the Dublin Core metadata of EPUB files,
+// stored in the OPF package document under <metadata>.
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/internal/fileutil"
+	"github.com/eduardo-moro/metadata-editor/internal/rawxml"
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+)
+
+const (
+	containerPath = "META-INF/container.xml"
+
+	// MimeType is the media type of EPUB files, stored in their mimetype entry
+	MimeType = "application/epub+zip"
+
+	opfMediaType = "application/oebps-package+xml"
+	opfNamespace = "http://www.idpf.org/2007/opf"
+	dcNamespace  = "http://purl.org/dc/elements/1.1/"
+)
+
+// errReadOnly is returned when saving a document opened with OpenReadOnly
+var errReadOnly = errors.New("document was opened read-only")
+
+// dcElements are the Dublin Core elements an OPF <metadata> can hold
+var dcElements = []string{
+	"title", "creator", "subject", "description", "publisher", "contributor",
+	"date", "type", "format", "identifier", "source", "language", "relation",
+	"coverage", "rights",
+}
+
+// EPUB represents an EPUB file with Dublin Core metadata
+type EPUB struct {
+	FilePath   string
+	DublinCore *dublincore.DublinCore
+	FileData   []byte // Store the file content in memory
+
+	// Deterministic makes Save produce byte-identical output for the same
+	// input and metadata, as with docx.DOCX.Deterministic
+	Deterministic bool
+
+	opfPath  string      // location of the OPF package document
+	pkg      *opfPackage // parsed OPF
+	original *dublincore.DublinCore
+}
+
+// container is META-INF/container.xml, which points to the OPF
+type container struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// opfPackage is the parsed OPF; manifest, spine and any other children are
+// kept verbatim after <metadata>, which the specification requires to be first
+type opfPackage struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr       `xml:",any,attr"`
+	Metadata opfMetadata      `xml:"http://www.idpf.org/2007/opf metadata"`
+	Rest     []rawxml.Element `xml:",any"`
+}
+
+// opfMetadata is the <metadata> element; XMLName is set when marshalling
+type opfMetadata struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr       `xml:",any,attr"`
+	Elements []rawxml.Element `xml:",any"`
+}
+
+// Open opens an EPUB file and reads its metadata
+func Open(filePath string) (*EPUB, error) {
+	// Read the entire file into memory
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	epub, err := OpenBytes(fileData)
+	if err != nil {
+		return nil, err
+	}
+	epub.FilePath = filePath
+
+	return epub, nil
+}
+
+// OpenBytes reads the metadata of an EPUB already in memory.
+// FilePath is left empty, so Save needs an explicit output path.
+func OpenBytes(fileData []byte) (*EPUB, error) {
+	// Create a zip reader from the file data
+	reader, err := zip.NewReader(bytes.NewReader(fileData), int64(len(fileData)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zip reader: %w", err)
+	}
+
+	epub, err := read(reader)
+	if err != nil {
+		return nil, err
+	}
+	epub.FileData = fileData
+
+	return epub, nil
+}
+
+// OpenReadOnly reads the metadata of an EPUB file without keeping the file
+// content in memory: only container.xml and the OPF are decompressed. The
+// returned document cannot be saved.
+func OpenReadOnly(filePath string) (*EPUB, error) {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zip reader: %w", err)
+	}
+	defer reader.Close()
+
+	epub, err := read(&reader.Reader)
+	if err != nil {
+		return nil, err
+	}
+	epub.FilePath = filePath
+
+	return epub, nil
+}
+
+// read parses the metadata of the EPUB in reader, touching no entry but
+// container.xml and the OPF
+func read(reader *zip.Reader) (*EPUB, error) {
+	opfPath, err := findOPF(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	opfFile, err := ziputil.FindFile(reader, opfPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", dublincore.ErrMalformedMetadata, err)
+	}
+	opfData, err := ziputil.ReadFile(opfFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read %s: %v", dublincore.ErrMalformedMetadata, opfPath, err)
+	}
+	pkg, err := parseOPF(opfData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", dublincore.ErrMalformedMetadata, opfPath, err)
+	}
+
+	dc := pkg.dublinCore()
+	return &EPUB{
+		DublinCore: dc,
+		opfPath:    opfPath,
+		pkg:        pkg,
+		original:   dc.Clone(),
+	}, nil
+}
+
+// findOPF returns the path of the OPF package document listed in container.xml
+func findOPF(reader *zip.Reader) (string, error) {
+	containerFile, err := ziputil.FindFile(reader, containerPath)
+	if err != nil {
+		return "", fmt.Errorf("not a valid EPUB: missing %s", containerPath)
+	}
+	data, err := ziputil.ReadFile(containerFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", containerPath, err)
+	}
+
+	var c container
+	if err := xml.Unmarshal(data, &c); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", containerPath, err)
+	}
+	for _, rootfile := range c.Rootfiles {
+		if rootfile.MediaType == opfMediaType && rootfile.FullPath != "" {
+			return rootfile.FullPath, nil
+		}
+	}
+	return "", errors.New("not a valid EPUB: container.xml lists no OPF package document")
+}
+
+// GetMetadata returns the document's Dublin Core metadata
+func (e *EPUB) GetMetadata() *dublincore.DublinCore {
+	return e.DublinCore
+}
+
+// SetMetadata replaces the document's Dublin Core metadata
+func (e *EPUB) SetMetadata(dc *dublincore.DublinCore) {
+	e.DublinCore = dc
+}
+
+// Save saves the EPUB file with updated metadata
+func (e *EPUB) Save(outputPath string) error {
+	if outputPath == "" {
+		outputPath = e.FilePath
+	}
+	if outputPath == "" {
+		return fmt.Errorf("no output path: document was opened from memory")
+	}
+
+	// Check before creating the output so a failed save doesn't truncate it
+	if e.FileData == nil {
+		return errReadOnly
+	}
+
+	// Write next to the output and rename, so a failed save never leaves a truncated file
+	return fileutil.WriteAtomic(outputPath, e.SaveTo)
+}
+
+// SaveTo writes the EPUB with updated metadata to w. Only the Dublin Core
+// elements whose values changed are rewritten, so attributes such as the id
+// referenced by the package's unique-identifier survive on the others.
+func (e *EPUB) SaveTo(w io.Writer) error {
+	if e.FileData == nil {
+		return errReadOnly
+	}
+
+	// Create a zip reader from the original file data
+	reader, err := zip.NewReader(bytes.NewReader(e.FileData), int64(len(e.FileData)))
+	if err != nil {
+		return fmt.Errorf("failed to create zip reader from memory: %w", err)
+	}
+
+	data, err := e.pkg.toXML(e.changedFields())
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", e.opfPath, err)
+	}
+
+	zipWriter := zip.NewWriter(w)
+
+	// Copy all files, replacing the OPF with updated metadata
+	for _, file := range reader.File {
+		if file.Name == e.opfPath {
+			opfWriter, err := ziputil.CreateRegenerated(zipWriter, file, e.Deterministic)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", e.opfPath, err)
+			}
+			if _, err := opfWriter.Write(data); err != nil {
+				return fmt.Errorf("failed to write %s: %w", e.opfPath, err)
+			}
+			continue
+		}
+
+		if err := ziputil.CopyFile(zipWriter, file, e.Deterministic); err != nil {
+			return fmt.Errorf("failed to copy file %s: %w", file.Name, err)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finish zip archive: %w", err)
+	}
+	return nil
+}
+
+// changedFields returns the Dublin Core elements whose values or language
+// tags differ from the parsed ones
+func (e *EPUB) changedFields() []rawxml.Field {
+	current := e.DublinCore.Sanitized()
+	var fields []rawxml.Field
+	for _, name := range dcElements {
+		values, _ := current.LangValues(name)
+		before, _ := e.original.LangValues(name)
+		if slices.Equal(values, before) {
+			continue
+		}
+		field := rawxml.Field{Name: xml.Name{Space: dcNamespace, Local: name}}
+		for _, value := range values {
+			field.Values = append(field.Values, value.Value)
+			field.Langs = append(field.Langs, value.Lang)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// parseOPF parses the OPF package document, keeping everything for round-trip
+func parseOPF(data []byte) (*opfPackage, error) {
+	var pkg opfPackage
+	if err := xml.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("XML parsing failed: %w", err)
+	}
+	if pkg.XMLName.Space != opfNamespace || pkg.XMLName.Local != "package" {
+		return nil, fmt.Errorf("root element is %s, not an OPF package", pkg.XMLName.Local)
+	}
+	return &pkg, nil
+}
+
+// dublinCore maps the dc: elements of <metadata> to Dublin Core, keeping the
+// xml:lang of titles and descriptions
+func (p *opfPackage) dublinCore() *dublincore.DublinCore {
+	dc := &dublincore.DublinCore{}
+	for _, element := range p.Metadata.Elements {
+		if element.XMLName.Space != dcNamespace {
+			continue
+		}
+		values, err := dc.LangValues(element.XMLName.Local)
+		if err != nil {
+			continue
+		}
+		if text, err := element.Text(); err == nil {
+			value := dublincore.LangString{Lang: element.Lang(), Value: text}
+			dc.SetLangValues(element.XMLName.Local, append(values, value))
+		}
+	}
+	return dc
+}
+
+// toXML regenerates the OPF with the given Dublin Core elements replaced
+func (p *opfPackage) toXML(fields []rawxml.Field) ([]byte, error) {
+	prefixes := rawxml.Prefixes(append(append([]xml.Attr{}, p.Attrs...), p.Metadata.Attrs...))
+
+	// Make sure the dc prefix is declared before writing dc: elements
+	metadataAttrs := append([]xml.Attr{}, p.Metadata.Attrs...)
+	if _, ok := prefixes[dcNamespace]; !ok {
+		prefixes[dcNamespace] = "dc"
+		metadataAttrs = append(metadataAttrs, xml.Attr{Name: xml.Name{Space: "xmlns", Local: "dc"}, Value: dcNamespace})
+	}
+	for i := range fields {
+		fields[i].Literal = rawxml.QualifyName(fields[i].Name, prefixes).Local
+	}
+
+	out := &opfPackage{
+		XMLName: rawxml.QualifyName(p.XMLName, prefixes),
+		Metadata: opfMetadata{
+			XMLName: rawxml.QualifyName(xml.Name{Space: opfNamespace, Local: "metadata"}, prefixes),
+		},
+	}
+	for _, attr := range p.Attrs {
+		out.Attrs = append(out.Attrs, rawxml.QualifyAttr(attr, prefixes))
+	}
+	for _, attr := range metadataAttrs {
+		out.Metadata.Attrs = append(out.Metadata.Attrs, rawxml.QualifyAttr(attr, prefixes))
+	}
+	for _, element := range rawxml.Merge(p.Metadata.Elements, fields) {
+		out.Metadata.Elements = append(out.Metadata.Elements, element.WithPrefixes(prefixes))
+	}
+	for _, element := range p.Rest {
+		out.Rest = append(out.Rest, element.WithPrefixes(prefixes))
+	}
+
+	header := `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(header + string(data)), nil
+}
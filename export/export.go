@@ -0,0 +1,46 @@
+// Package export renders Dublin Core metadata in formats meant for other
+// systems, such as JSON-LD for the web.
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// dcTermsNamespace is the DCMI Metadata Terms vocabulary
+const dcTermsNamespace = "http://purl.org/dc/terms/"
+
+// corePropertiesNamespace holds keywords and category, which aren't DCMI terms
+const corePropertiesNamespace = "http://schemas.openxmlformats.org/package/2006/metadata/core-properties/"
+
+// member is a key of an object that keeps its insertion order when encoded
+type member struct {
+	Key   string
+	Value any
+}
+
+// object is a JSON object whose members are encoded in order
+type object []member
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
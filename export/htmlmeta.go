@@ -0,0 +1,51 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+)
+
+// dcElementsNamespace is the namespace the DC. prefix is bound to in HTML
+const dcElementsNamespace = "http://purl.org/dc/elements/1.1/"
+
+// HTMLMeta renders dc as the Dublin Core HTML metadata set: a schema.DC link
+// followed by one <meta name="DC.element"> per value, with lang attributes for
+// language-tagged titles and descriptions. Keywords, which aren't a DC
+// element, become the standard HTML keywords meta tag.
+func HTMLMeta(dc *dublincore.DublinCore) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<link rel=\"schema.DC\" href=\"%s\">\n", dcElementsNamespace)
+
+	for _, name := range dublincore.FieldNames() {
+		if name == "keywords" || name == "category" {
+			continue
+		}
+		values, _ := dc.Get(name)
+		for _, value := range values {
+			if value != "" {
+				writeMeta(&buf, "DC."+name, value, "")
+			}
+		}
+		for _, tagged := range langValues(dc, name) {
+			writeMeta(&buf, "DC."+name, tagged.Value, tagged.Lang)
+		}
+	}
+
+	if len(dc.Keywords) > 0 {
+		writeMeta(&buf, "keywords", strings.Join(dc.Keywords, ", "), "")
+	}
+	return buf.Bytes()
+}
+
+// writeMeta writes a single <meta> tag with escaped attributes
+func writeMeta(buf *bytes.Buffer, name, content, lang string) {
+	fmt.Fprintf(buf, "<meta name=\"%s\"", html.EscapeString(name))
+	if lang != "" {
+		fmt.Fprintf(buf, " lang=\"%s\"", html.EscapeString(lang))
+	}
+	fmt.Fprintf(buf, " content=\"%s\">\n", html.EscapeString(content))
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"encoding/json"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+)
+
+// langValue is a JSON-LD value object carrying a language tag
+type langValue struct {
+	Value    string `json:"@value"`
+	Language string `json:"@language"`
+}
+
+// JSONLD renders dc as a JSON-LD document whose @context maps each element to
+// the DCMI terms vocabulary. Every populated field becomes an array, and
+// language-tagged titles and descriptions become @language value objects.
+func JSONLD(dc *dublincore.DublinCore) ([]byte, error) {
+	doc := object{{"@context", object{
+		{"@vocab", dcTermsNamespace},
+		{"keywords", corePropertiesNamespace + "keywords"},
+		{"category", corePropertiesNamespace + "category"},
+	}}}
+
+	for _, name := range dublincore.FieldNames() {
+		values, _ := dc.Get(name)
+
+		var items []any
+		for _, value := range values {
+			if value != "" {
+				items = append(items, value)
+			}
+		}
+		for _, tagged := range langValues(dc, name) {
+			items = append(items, langValue{Value: tagged.Value, Language: tagged.Lang})
+		}
+
+		if len(items) > 0 {
+			doc = append(doc, member{name, items})
+		}
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// langValues returns the language-tagged alternatives of a field
+func langValues(dc *dublincore.DublinCore, name string) []dublincore.LangString {
+	switch name {
+	case "title":
+		return dc.TitleLang
+	case "description":
+		return dc.DescriptionLang
+	}
+	return nil
+}
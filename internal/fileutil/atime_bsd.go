@@ -0,0 +1,18 @@
+//go:build darwin || freebsd || netbsd
+
+package fileutil
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// AccessTime returns the last access time recorded in info, falling back to
+// its modification time when the platform doesn't report one
+func AccessTime(info os.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(int64(stat.Atimespec.Sec), int64(stat.Atimespec.Nsec))
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,14 @@
+//go:build !(linux || openbsd || dragonfly || solaris || illumos || aix || darwin || freebsd || netbsd || windows)
+
+package fileutil
+
+import (
+	"os"
+	"time"
+)
+
+// AccessTime returns the modification time of info: this platform doesn't
+// report access times
+func AccessTime(info os.FileInfo) time.Time {
+	return info.ModTime()
+}
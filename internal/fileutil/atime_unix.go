@@ -0,0 +1,18 @@
+//go:build linux || openbsd || dragonfly || solaris || illumos || aix
+
+package fileutil
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// AccessTime returns the last access time recorded in info, falling back to
+// its modification time when the platform doesn't report one
+func AccessTime(info os.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec))
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,152 @@
+// Package rawxml keeps XML elements verbatim so property parts can be
+// rewritten without losing content the editor doesn't model.
+package rawxml
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+// xmlNamespace is the namespace bound to the reserved xml: prefix
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// Element is an XML element whose attributes and content are kept as parsed
+type Element struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   []byte     `xml:",innerxml"`
+}
+
+// Field is a modeled element whose values replace any parsed occurrences
+type Field struct {
+	Name    xml.Name // namespace-resolved name used to match parsed elements
+	Literal string   // prefixed name written to the output, e.g. "dc:title"
+	Values  []string
+	Langs   []string // xml:lang of each value, "" or missing for untagged ones
+}
+
+// TextElement builds an element containing only escaped character data
+func TextElement(name, value string) Element {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(value))
+	return Element{XMLName: xml.Name{Local: name}, Inner: buf.Bytes()}
+}
+
+// Text returns the character data of the element
+func (e Element) Text() (string, error) {
+	var text string
+	err := xml.Unmarshal([]byte("<v>"+string(e.Inner)+"</v>"), &text)
+	return text, err
+}
+
+// Lang returns the xml:lang attribute of the element, or "" if it has none
+func (e Element) Lang() string {
+	for _, attr := range e.Attrs {
+		if attr.Name.Space == xmlNamespace && attr.Name.Local == "lang" {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// Is reports whether the element has the given namespace and local name
+func (e Element) Is(name xml.Name) bool {
+	return e.XMLName.Space == name.Space && e.XMLName.Local == name.Local
+}
+
+// WithPrefixes rewrites namespace URIs into the literal prefixes used when
+// marshalling, e.g. "http://purl.org/dc/elements/1.1/" becomes "dc:".
+// A prefix of "" drops the namespace so the element inherits the default one.
+func (e Element) WithPrefixes(prefixes map[string]string) Element {
+	out := Element{
+		XMLName: QualifyName(e.XMLName, prefixes),
+		Inner:   e.Inner,
+	}
+	for _, attr := range e.Attrs {
+		out.Attrs = append(out.Attrs, QualifyAttr(attr, prefixes))
+	}
+	return out
+}
+
+// QualifyAttr maps a namespace-resolved attribute to its prefixed form,
+// including xmlns declarations
+func QualifyAttr(attr xml.Attr, prefixes map[string]string) xml.Attr {
+	if attr.Name.Space == "xmlns" {
+		attr.Name = xml.Name{Local: "xmlns:" + attr.Name.Local}
+	} else {
+		attr.Name = QualifyName(attr.Name, prefixes)
+	}
+	return attr
+}
+
+// QualifyName maps a namespace-resolved name to its prefixed form
+func QualifyName(name xml.Name, prefixes map[string]string) xml.Name {
+	prefix, ok := prefixes[name.Space]
+	if !ok || name.Space == "" {
+		return name
+	}
+	if prefix == "" {
+		return xml.Name{Local: name.Local}
+	}
+	return xml.Name{Local: prefix + ":" + name.Local}
+}
+
+// Prefixes builds a namespace URI to prefix map from xmlns declarations
+func Prefixes(attrs []xml.Attr) map[string]string {
+	prefixes := map[string]string{}
+	for _, attr := range attrs {
+		switch {
+		case attr.Name.Space == "xmlns":
+			prefixes[attr.Value] = attr.Name.Local
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			prefixes[attr.Value] = ""
+		}
+	}
+	return prefixes
+}
+
+// Merge replaces the elements matching each field with the field's values.
+// Replacements are emitted where the field first occurred, empty fields
+// remove their elements, and fields absent from elements are appended in order.
+func Merge(elements []Element, fields []Field) []Element {
+	var out []Element
+	written := make([]bool, len(fields))
+
+	for _, element := range elements {
+		index := -1
+		for i, field := range fields {
+			if element.Is(field.Name) {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			out = append(out, element)
+			continue
+		}
+		if !written[index] {
+			written[index] = true
+			out = append(out, fields[index].elements()...)
+		}
+	}
+
+	for i, field := range fields {
+		if !written[i] {
+			out = append(out, field.elements()...)
+		}
+	}
+
+	return out
+}
+
+func (f Field) elements() []Element {
+	var out []Element
+	for i, value := range f.Values {
+		element := TextElement(f.Literal, value)
+		if i < len(f.Langs) && f.Langs[i] != "" {
+			element.Attrs = []xml.Attr{{Name: xml.Name{Local: "xml:lang"}, Value: f.Langs[i]}}
+		}
+		out = append(out, element)
+	}
+	return out
+}
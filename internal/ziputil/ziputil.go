@@ -0,0 +1,160 @@
+// Package ziputil holds the zip helpers shared by the document formats.
+package ziputil
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// FindFile returns the entry with the given name. An exact match is
+// preferred; otherwise the first entry whose name differs only in case is used,
+// since some producers write e.g. "DocProps/Core.xml".
+func FindFile(reader *zip.Reader, name string) (*zip.File, error) {
+	for _, file := range reader.File {
+		if file.Name == name {
+			return file, nil
+		}
+	}
+	for _, file := range reader.File {
+		if strings.EqualFold(file.Name, name) {
+			return file, nil
+		}
+	}
+	return nil, fmt.Errorf("file not found: %s", name)
+}
+
+// DuplicateNames returns the entry names that occur more than once, compared
+// without case as OPC part names are, in order of first occurrence
+func DuplicateNames(reader *zip.Reader) []string {
+	count := map[string]int{}
+	for _, file := range reader.File {
+		count[strings.ToLower(file.Name)]++
+	}
+
+	var names []string
+	for _, file := range reader.File {
+		key := strings.ToLower(file.Name)
+		if count[key] > 1 {
+			names = append(names, file.Name)
+			count[key] = 0
+		}
+	}
+	return names
+}
+
+// MaxPartSize bounds how much of a single entry ReadFile decompresses, so a
+// crafted archive can't make the editor exhaust memory on a metadata part
+const MaxPartSize = 32 << 20
+
+// ErrPartTooLarge is returned by ReadFile for entries larger than MaxPartSize
+var ErrPartTooLarge = errors.New("zip entry too large")
+
+// ReadFile returns the uncompressed content of an entry, failing with
+// ErrPartTooLarge past MaxPartSize whatever size the header claims
+func ReadFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > MaxPartSize {
+		return nil, fmt.Errorf("%w: %s", ErrPartTooLarge, file.Name)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, MaxPartSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxPartSize {
+		return nil, fmt.Errorf("%w: %s", ErrPartTooLarge, file.Name)
+	}
+	return data, nil
+}
+
+// CopyFile copies an entry without recompressing it, so its compression
+// method, CRC and sizes stay exactly as in the original archive. With
+// zeroTimes the entry's timestamps are cleared as ClearTimes does.
+func CopyFile(dest *zip.Writer, src *zip.File, zeroTimes bool) error {
+	srcReader, err := src.OpenRaw()
+	if err != nil {
+		return err
+	}
+
+	header := src.FileHeader
+	if zeroTimes {
+		ClearTimes(&header)
+	}
+	destWriter, err := dest.CreateRaw(&header)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(destWriter, srcReader)
+	return err
+}
+
+// CreateRegenerated creates the entry for a regenerated part, keeping the
+// original name, compression method and modification time (cleared with zeroTimes)
+func CreateRegenerated(dest *zip.Writer, src *zip.File, zeroTimes bool) (io.Writer, error) {
+	header := &zip.FileHeader{
+		Name:     src.Name,
+		Method:   src.Method,
+		Modified: src.Modified,
+	}
+	if zeroTimes {
+		ClearTimes(header)
+	}
+	return dest.CreateHeader(header)
+}
+
+// CreateNew creates a deflated entry for a part that wasn't in the original
+// archive, stamped with the current time (cleared with zeroTimes)
+func CreateNew(dest *zip.Writer, name string, zeroTimes bool) (io.Writer, error) {
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	}
+	if zeroTimes {
+		ClearTimes(header)
+	}
+	return dest.CreateHeader(header)
+}
+
+// dosEpoch is 1980-01-01 encoded as an MS-DOS date, the earliest a zip header can hold
+const dosEpoch = 1<<5 | 1
+
+// timestampExtraIDs are the extra fields that carry file times: NTFS,
+// extended timestamp and the old Info-ZIP Unix field
+var timestampExtraIDs = map[uint16]bool{0x000a: true, 0x5455: true, 0x5855: true}
+
+// ClearTimes sets the entry's modification time to the MS-DOS epoch and drops
+// extra fields holding timestamps, so the header no longer depends on when
+// the archive was written
+func ClearTimes(header *zip.FileHeader) {
+	header.Modified = time.Time{}
+	header.ModifiedTime = 0
+	header.ModifiedDate = dosEpoch
+
+	var extra []byte
+	for rest := header.Extra; len(rest) >= 4; {
+		id := binary.LittleEndian.Uint16(rest[0:2])
+		size := int(binary.LittleEndian.Uint16(rest[2:4]))
+		if 4+size > len(rest) {
+			// Malformed trailing field; keep it as it was
+			extra = append(extra, rest...)
+			break
+		}
+		if !timestampExtraIDs[id] {
+			extra = append(extra, rest[:4+size]...)
+		}
+		rest = rest[4+size:]
+	}
+	header.Extra = extra
+}
@@ -0,0 +1,170 @@
+package metadata
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/docx"
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/epub"
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+	"github.com/eduardo-moro/metadata-editor/odt"
+)
+
+// ErrUnsupportedFormat is returned when a file is not a known document format
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Document is a file whose Dublin Core metadata can be read, changed and saved
+type Document interface {
+	GetMetadata() *dublincore.DublinCore
+	SetMetadata(dc *dublincore.DublinCore)
+	Save(outputPath string) error
+	SaveTo(w io.Writer) error
+}
+
+// Format identifies the container format of a document
+type Format string
+
+const (
+	FormatDOCX Format = "docx"
+	FormatPPTX Format = "pptx"
+	FormatXLSX Format = "xlsx"
+	FormatODT  Format = "odt"
+	FormatEPUB Format = "epub"
+)
+
+// extensions maps the file extensions handled by the editor to their format
+var extensions = map[string]Format{
+	".docx": FormatDOCX,
+	".pptx": FormatPPTX,
+	".xlsx": FormatXLSX,
+	".odt":  FormatODT,
+	".epub": FormatEPUB,
+}
+
+// officeFormats maps the MIME types reported by docx.DocumentType to their format
+var officeFormats = map[string]Format{
+	docx.MimeTypeDOCX: FormatDOCX,
+	docx.MimeTypePPTX: FormatPPTX,
+	docx.MimeTypeXLSX: FormatXLSX,
+}
+
+// IsOffice reports whether f is a Word, PowerPoint or Excel format, all of
+// which are handled by the docx package
+func (f Format) IsOffice() bool {
+	return f == FormatDOCX || f == FormatPPTX || f == FormatXLSX
+}
+
+// Open detects the format of the file at path and opens it with the matching handler
+func Open(path string) (Document, error) {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatDOCX, FormatPPTX, FormatXLSX:
+		return docx.Open(path)
+	case FormatODT:
+		return odt.Open(path)
+	case FormatEPUB:
+		return epub.Open(path)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+}
+
+// OpenReadOnly is like Open but only decompresses the parts holding the
+// metadata and doesn't keep the file in memory, so scanning directories of
+// large documents stays cheap. The returned document cannot be saved.
+func OpenReadOnly(path string) (Document, error) {
+	format, err := DetectFormat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatDOCX, FormatPPTX, FormatXLSX:
+		return docx.OpenReadOnly(path)
+	case FormatODT:
+		return odt.OpenReadOnly(path)
+	case FormatEPUB:
+		return epub.OpenReadOnly(path)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+}
+
+// DetectFormat sniffs the zip contents of path to find its document format
+func DetectFormat(path string) (Format, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %v", ErrUnsupportedFormat, path, err)
+	}
+	defer reader.Close()
+
+	if format, ok := detectZip(&reader.Reader); ok {
+		return format, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, path)
+}
+
+// OpenBytes detects the format of a document held in memory, such as one
+// read from stdin, and opens it with the matching handler
+func OpenBytes(data []byte) (Document, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+
+	format, ok := detectZip(reader)
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+
+	switch format {
+	case FormatDOCX, FormatPPTX, FormatXLSX:
+		return docx.OpenBytes(data)
+	case FormatODT:
+		return odt.OpenBytes(data)
+	default:
+		return epub.OpenBytes(data)
+	}
+}
+
+// detectZip identifies the document format from the entries of a zip archive
+func detectZip(reader *zip.Reader) (Format, bool) {
+	if mimeType, err := docx.DocumentType(reader); err == nil {
+		return officeFormats[mimeType], true
+	}
+	if _, err := ziputil.FindFile(reader, "docProps/core.xml"); err == nil {
+		return FormatDOCX, true
+	}
+
+	if _, err := ziputil.FindFile(reader, "meta.xml"); err == nil {
+		return FormatODT, true
+	}
+	if mimetype, err := ziputil.FindFile(reader, "mimetype"); err == nil {
+		data, err := ziputil.ReadFile(mimetype)
+		if err == nil && strings.HasPrefix(string(data), "application/vnd.oasis.opendocument") {
+			return FormatODT, true
+		}
+		if err == nil && strings.TrimSpace(string(data)) == epub.MimeType {
+			return FormatEPUB, true
+		}
+	}
+	if _, err := ziputil.FindFile(reader, "META-INF/container.xml"); err == nil {
+		return FormatEPUB, true
+	}
+
+	return "", false
+}
+
+// HasSupportedExtension reports whether path has the extension of a supported format
+func HasSupportedExtension(path string) bool {
+	_, ok := extensions[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
@@ -0,0 +1,7 @@
+package metadata
+
+// Version is the version of the library and the editor. It is a variable so
+// release builds can set it with:
+//
+//	go build -ldflags "-X github.com/eduardo-moro/metadata-editor/metadata.Version=v1.2.0"
+var Version = "dev"
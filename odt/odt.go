@@ -0,0 +1,317 @@
+package odt
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+	"github.com/eduardo-moro/metadata-editor/internal/fileutil"
+	"github.com/eduardo-moro/metadata-editor/internal/rawxml"
+	"github.com/eduardo-moro/metadata-editor/internal/ziputil"
+)
+
+const (
+	metaPath = "meta.xml"
+
+	// MimeType is the media type of OpenDocument text files
+	MimeType = "application/vnd.oasis.opendocument.text"
+
+	officeNamespace = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	metaNamespace   = "urn:oasis:names:tc:opendocument:xmlns:meta:1.0"
+	dcNamespace     = "http://purl.org/dc/elements/1.1/"
+)
+
+// errNoMeta is returned when saving a document that has no meta.xml
+var errNoMeta = fmt.Errorf("document has no %s to store metadata in", metaPath)
+
+// errReadOnly is returned when saving a document opened with OpenReadOnly
+var errReadOnly = errors.New("document was opened read-only")
+
+// ODT represents an OpenDocument text file with Dublin Core metadata
+type ODT struct {
+	FilePath   string
+	DublinCore *dublincore.DublinCore
+	FileData   []byte // Store the file content in memory
+
+	// Deterministic makes Save produce byte-identical output for the same
+	// input and metadata, as with docx.DOCX.Deterministic
+	Deterministic bool
+
+	meta *documentMeta // parsed meta.xml, nil when the file has none
+}
+
+// documentMeta is the parsed meta.xml; every office:meta child is kept so
+// unmodeled ones (generator, statistics, ...) survive a Save
+type documentMeta struct {
+	XMLName xml.Name     `xml:"urn:oasis:names:tc:opendocument:xmlns:office:1.0 document-meta"`
+	Attrs   []xml.Attr   `xml:",any,attr"`
+	Meta    metaElements `xml:"urn:oasis:names:tc:opendocument:xmlns:office:1.0 meta"`
+}
+
+// metaElements is the office:meta element; XMLName is set when marshalling
+type metaElements struct {
+	XMLName  xml.Name
+	Elements []rawxml.Element `xml:",any"`
+}
+
+// documentMetaXML is the serialized form of meta.xml
+type documentMetaXML struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Meta    metaElements
+}
+
+// metaFields maps the modeled elements to their Dublin Core field names
+var metaFields = []struct {
+	Name  xml.Name
+	Field string
+}{
+	{xml.Name{Space: dcNamespace, Local: "title"}, "title"},
+	{xml.Name{Space: dcNamespace, Local: "creator"}, "creator"},
+	{xml.Name{Space: dcNamespace, Local: "subject"}, "subject"},
+	{xml.Name{Space: dcNamespace, Local: "description"}, "description"},
+	{xml.Name{Space: metaNamespace, Local: "keyword"}, "keywords"},
+}
+
+// Open opens an ODT file and reads its metadata
+func Open(filePath string) (*ODT, error) {
+	// Read the entire file into memory
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	odt, err := OpenBytes(fileData)
+	if err != nil {
+		return nil, err
+	}
+	odt.FilePath = filePath
+
+	return odt, nil
+}
+
+// OpenBytes reads the metadata of an ODT already in memory.
+// FilePath is left empty, so Save needs an explicit output path.
+func OpenBytes(fileData []byte) (*ODT, error) {
+	// Create a zip reader from the file data
+	reader, err := zip.NewReader(bytes.NewReader(fileData), int64(len(fileData)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zip reader: %w", err)
+	}
+
+	odt, err := read(reader)
+	if err != nil {
+		return nil, err
+	}
+	odt.FileData = fileData
+
+	return odt, nil
+}
+
+// OpenReadOnly reads the metadata of an ODT file without keeping the file
+// content in memory: only meta.xml is decompressed. The returned document
+// cannot be saved.
+func OpenReadOnly(filePath string) (*ODT, error) {
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zip reader: %w", err)
+	}
+	defer reader.Close()
+
+	odt, err := read(&reader.Reader)
+	if err != nil {
+		return nil, err
+	}
+	odt.FilePath = filePath
+
+	return odt, nil
+}
+
+// read parses the metadata of the ODT in reader, touching no entry but meta.xml
+func read(reader *zip.Reader) (*ODT, error) {
+	odt := &ODT{
+		DublinCore: newDublinCore(),
+	}
+
+	// Read existing Dublin Core metadata; a missing meta.xml keeps the defaults
+	if metaFile, err := ziputil.FindFile(reader, metaPath); err == nil {
+		metaData, err := ziputil.ReadFile(metaFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read %s: %v", dublincore.ErrMalformedMetadata, metaPath, err)
+		}
+		meta, err := parseMetaXML(metaData)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", dublincore.ErrMalformedMetadata, metaPath, err)
+		}
+		odt.meta = meta
+		odt.DublinCore = meta.dublinCore()
+	}
+
+	return odt, nil
+}
+
+// GetMetadata returns the document's Dublin Core metadata
+func (o *ODT) GetMetadata() *dublincore.DublinCore {
+	return o.DublinCore
+}
+
+// SetMetadata replaces the document's Dublin Core metadata
+func (o *ODT) SetMetadata(dc *dublincore.DublinCore) {
+	o.DublinCore = dc
+}
+
+// Save saves the ODT file with updated metadata
+func (o *ODT) Save(outputPath string) error {
+	if outputPath == "" {
+		outputPath = o.FilePath
+	}
+	if outputPath == "" {
+		return fmt.Errorf("no output path: document was opened from memory")
+	}
+
+	// Check before creating the output so a failed save doesn't truncate it
+	if o.meta == nil {
+		return errNoMeta
+	}
+	if o.FileData == nil {
+		return errReadOnly
+	}
+
+	// Write next to the output and rename, so a failed save never leaves a truncated file
+	return fileutil.WriteAtomic(outputPath, o.SaveTo)
+}
+
+// SaveTo writes the ODT with updated metadata to w
+func (o *ODT) SaveTo(w io.Writer) error {
+	if o.meta == nil {
+		return errNoMeta
+	}
+	if o.FileData == nil {
+		return errReadOnly
+	}
+
+	// Create a zip reader from the original file data
+	reader, err := zip.NewReader(bytes.NewReader(o.FileData), int64(len(o.FileData)))
+	if err != nil {
+		return fmt.Errorf("failed to create zip reader from memory: %w", err)
+	}
+
+	data, err := o.meta.toXML(o.DublinCore.Sanitized())
+	if err != nil {
+		return fmt.Errorf("failed to marshal meta.xml: %w", err)
+	}
+
+	zipWriter := zip.NewWriter(w)
+
+	// Copy all files, replacing meta.xml with updated metadata
+	for _, file := range reader.File {
+		if file.Name == metaPath {
+			metaWriter, err := ziputil.CreateRegenerated(zipWriter, file, o.Deterministic)
+			if err != nil {
+				return fmt.Errorf("failed to create meta.xml: %w", err)
+			}
+			if _, err := metaWriter.Write(data); err != nil {
+				return fmt.Errorf("failed to write meta.xml: %w", err)
+			}
+			continue
+		}
+
+		if err := ziputil.CopyFile(zipWriter, file, o.Deterministic); err != nil {
+			return fmt.Errorf("failed to copy file %s: %w", file.Name, err)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finish zip archive: %w", err)
+	}
+	return nil
+}
+
+// parseMetaXML parses meta.xml, keeping unknown elements for round-trip
+func parseMetaXML(data []byte) (*documentMeta, error) {
+	var meta documentMeta
+	if err := xml.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("XML parsing failed: %w", err)
+	}
+	return &meta, nil
+}
+
+// dublinCore maps the modeled office:meta elements to Dublin Core, keeping
+// the xml:lang of titles and descriptions
+func (m *documentMeta) dublinCore() *dublincore.DublinCore {
+	dc := newDublinCore()
+	for _, element := range m.Meta.Elements {
+		for _, mf := range metaFields {
+			if !element.Is(mf.Name) {
+				continue
+			}
+			if text, err := element.Text(); err == nil {
+				values, _ := dc.LangValues(mf.Field)
+				dc.SetLangValues(mf.Field, append(values, dublincore.LangString{Lang: element.Lang(), Value: text}))
+			}
+		}
+	}
+	return dc
+}
+
+// toXML regenerates meta.xml with the modeled elements taken from dc
+func (m *documentMeta) toXML(dc *dublincore.DublinCore) ([]byte, error) {
+	attrs := append([]xml.Attr{}, m.Attrs...)
+	prefixes := rawxml.Prefixes(attrs)
+
+	// Make sure every namespace we write has a declared prefix
+	for namespace, prefix := range map[string]string{
+		officeNamespace: "office",
+		metaNamespace:   "meta",
+		dcNamespace:     "dc",
+	} {
+		if _, ok := prefixes[namespace]; !ok {
+			prefixes[namespace] = prefix
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Space: "xmlns", Local: prefix}, Value: namespace})
+		}
+	}
+
+	var fields []rawxml.Field
+	for _, mf := range metaFields {
+		field := rawxml.Field{Name: mf.Name, Literal: rawxml.QualifyName(mf.Name, prefixes).Local}
+		values, _ := dc.LangValues(mf.Field)
+		for _, value := range values {
+			field.Values = append(field.Values, value.Value)
+			field.Langs = append(field.Langs, value.Lang)
+		}
+		fields = append(fields, field)
+	}
+
+	out := &documentMetaXML{
+		XMLName: rawxml.QualifyName(xml.Name{Space: officeNamespace, Local: "document-meta"}, prefixes),
+		Meta: metaElements{
+			XMLName: rawxml.QualifyName(xml.Name{Space: officeNamespace, Local: "meta"}, prefixes),
+		},
+	}
+	for _, attr := range attrs {
+		out.Attrs = append(out.Attrs, rawxml.QualifyAttr(attr, prefixes))
+	}
+	for _, element := range rawxml.Merge(m.Meta.Elements, fields) {
+		out.Meta.Elements = append(out.Meta.Elements, element.WithPrefixes(prefixes))
+	}
+
+	header := `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(header + string(data)), nil
+}
+
+// newDublinCore returns the defaults for an ODT document
+func newDublinCore() *dublincore.DublinCore {
+	dc := dublincore.New()
+	dc.Format = []string{MimeType}
+	return dc
+}
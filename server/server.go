@@ -0,0 +1,192 @@
+// Package server exposes the metadata editor over HTTP. Documents are
+// handled in memory, so uploads never touch the filesystem.
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/eduardo-moro/metadata-editor/docx"
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+)
+
+// DefaultMaxUploadSize is the request size limit used when none is given
+const DefaultMaxUploadSize = 20 << 20
+
+// Handler serves the metadata API:
+//
+//	POST /metadata  DOCX upload → JSON metadata
+//	PUT  /metadata  multipart DOCX ("file") + JSON ("metadata") → edited DOCX
+type Handler struct {
+	// MaxUploadSize caps the request body in bytes
+	MaxUploadSize int64
+}
+
+// NewHandler returns a Handler limiting request bodies to maxUploadSize bytes
+// (DefaultMaxUploadSize when zero or negative)
+func NewHandler(maxUploadSize int64) *Handler {
+	if maxUploadSize <= 0 {
+		maxUploadSize = DefaultMaxUploadSize
+	}
+	return &Handler{MaxUploadSize: maxUploadSize}
+}
+
+// ServeHTTP routes /metadata requests by method
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/metadata" {
+		http.NotFound(w, r)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.MaxUploadSize)
+
+	switch r.Method {
+	case http.MethodPost:
+		h.readMetadata(w, r)
+	case http.MethodPut:
+		h.writeMetadata(w, r)
+	default:
+		w.Header().Set("Allow", "POST, PUT")
+		httpError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	}
+}
+
+// readMetadata returns the metadata of the uploaded document as JSON
+func (h *Handler) readMetadata(w http.ResponseWriter, r *http.Request) {
+	data, _, status, err := h.readDocument(r)
+	if err != nil {
+		httpError(w, status, err)
+		return
+	}
+
+	doc, err := docx.OpenBytes(data)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := doc.GetMetadata().ToJSON()
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(out)
+}
+
+// writeMetadata merges the uploaded JSON into the uploaded document and returns it
+func (h *Handler) writeMetadata(w http.ResponseWriter, r *http.Request) {
+	if mediaType(r) != "multipart/form-data" {
+		httpError(w, http.StatusUnsupportedMediaType, errors.New("expected multipart/form-data with \"file\" and \"metadata\" parts"))
+		return
+	}
+
+	data, name, status, err := h.readDocument(r)
+	if err != nil {
+		httpError(w, status, err)
+		return
+	}
+
+	metadataJSON := r.FormValue("metadata")
+	if metadataJSON == "" {
+		httpError(w, http.StatusBadRequest, errors.New("missing \"metadata\" part"))
+		return
+	}
+	template, err := dublincore.FromJSON([]byte(metadataJSON))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, fmt.Errorf("invalid metadata JSON: %w", err))
+		return
+	}
+
+	doc, err := docx.OpenBytes(data)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err)
+		return
+	}
+	doc.GetMetadata().Merge(template, true)
+
+	changed, err := doc.Changed()
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Buffer the output so a failed save can still be reported as an error;
+	// an unchanged document is returned with its original bytes
+	out := bytes.NewBuffer(data)
+	if changed {
+		out = new(bytes.Buffer)
+		if err := doc.SaveTo(out); err != nil {
+			httpError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", doc.MimeType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	w.Write(out.Bytes())
+}
+
+// readDocument reads the uploaded DOCX, either as the raw request body or as
+// the "file" part of a multipart form. It returns the file name for the
+// response and the status to report on error.
+func (h *Handler) readDocument(r *http.Request) ([]byte, string, int, error) {
+	var body io.Reader
+	name := "document.docx"
+
+	switch mediaType(r) {
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(h.MaxUploadSize); err != nil {
+			return nil, "", uploadStatus(err), fmt.Errorf("invalid multipart upload: %w", err)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", http.StatusBadRequest, errors.New("missing \"file\" part")
+		}
+		defer file.Close()
+		body = file
+		if base := filepath.Base(header.Filename); base != "." && base != "/" {
+			name = base
+		}
+	case docx.MimeTypeDOCX, docx.MimeTypePPTX, docx.MimeTypeXLSX, "application/octet-stream":
+		body = r.Body
+	default:
+		return nil, "", http.StatusUnsupportedMediaType, fmt.Errorf("unsupported content type %q", r.Header.Get("Content-Type"))
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", uploadStatus(err), fmt.Errorf("failed to read upload: %w", err)
+	}
+	return data, name, http.StatusOK, nil
+}
+
+// mediaType returns the request's content type without parameters
+func mediaType(r *http.Request) string {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return strings.ToLower(mediaType)
+}
+
+// uploadStatus maps a body read error to 413 when the size limit was hit
+func uploadStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// httpError writes err as a JSON error body
+func httpError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
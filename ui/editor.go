@@ -1,8 +1,10 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -19,60 +21,185 @@ var (
 	placeholderStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
 )
 
+// formField describes one editable input of the form
+type formField struct {
+	name        string // DublinCore field name used with Get/Set
+	label       string
+	placeholder string
+	multiline   bool // edited in a textarea instead of a single-line input
+}
+
+// multi reports whether the field holds a comma-separated list of values
+func (f formField) multi() bool {
+	spec, _ := dublincore.FieldSpecFor(f.name)
+	return spec.Multi
+}
+
+// formFields lists the inputs in display order
+var formFields = []formField{
+	{"title", "DC: Title", "e.g., Senior Backend Developer", false},
+	{"creator", "DC: Creator (comma-separated)", "e.g., João Silva, Maria Santos", false},
+	{"keywords", "CP: Keywords (comma-separated)", "e.g., Go, Backend, Microservices, PHP", false},
+	{"description", "DC: Description (Enter adds a line)", "e.g., Experienced backend developer with 6+ years in technology", true},
+	{"subject", "DC: Subject (comma-separated)", "e.g., Software Engineering, Backend", false},
+	{"publisher", "DC: Publisher (comma-separated)", "e.g., ACME Corp", false},
+	{"contributor", "DC: Contributor (comma-separated)", "e.g., Maria Santos", false},
+	{"date", "DC: Date", "e.g., 2024-01-31", false},
+	{"type", "DC: Type", "e.g., Text", false},
+	{"format", "DC: Format", "e.g., application/vnd.openxmlformats-officedocument.wordprocessingml.document", false},
+	{"identifier", "DC: Identifier", "e.g., https://example.com/cv", false},
+	{"source", "DC: Source", "e.g., https://example.com/original", false},
+	{"language", "DC: Language (comma-separated)", "e.g., pt-BR, en", false},
+	{"relation", "DC: Relation", "e.g., https://example.com/portfolio", false},
+	{"coverage", "DC: Coverage", "e.g., Brazil, 2020-2024", false},
+	{"rights", "DC: Rights", "e.g., All rights reserved", false},
+}
+
+const (
+	headerLines = 2 // title bar
+	footerLines = 8 // category, help and submit button
+	fieldLines  = 3 // label, input and spacing
+	areaHeight  = 5 // visible lines of a multiline input
+	areaWidth   = 72
+)
+
 type model struct {
 	inputs    []textinput.Model
+	areas     []textarea.Model // used instead of inputs[i] for multiline fields
+	initial   []string         // value each field started with, to tell edits from untouched fields
 	focused   int
+	offset    int // index of the first visible input
+	height    int // terminal height, 0 until the first WindowSizeMsg
 	dc        *dublincore.DublinCore
 	done      bool
 	cancelled bool
+
+	// confirming shows the summary of pending changes before quitting
+	confirming bool
+	pending    *dublincore.DublinCore
+
+	// vocabulary holds the terms the keywords input suggests
+	vocabulary []string
 }
 
-func initialModel(dc *dublincore.DublinCore) model {
+func initialModel(dc *dublincore.DublinCore, vocabulary []string) model {
 	m := model{
-		inputs: make([]textinput.Model, 4),
-		dc:     dc,
+		inputs:     make([]textinput.Model, len(formFields)),
+		areas:      make([]textarea.Model, len(formFields)),
+		initial:    make([]string, len(formFields)),
+		dc:         dc,
+		vocabulary: vocabulary,
 	}
 
-	// Title input
-	m.inputs[0] = textinput.New()
-	m.inputs[0].Placeholder = "e.g., Senior Backend Developer"
-	m.inputs[0].PlaceholderStyle = placeholderStyle
-	m.inputs[0].Focus()
-	m.inputs[0].PromptStyle = focusedStyle
-	m.inputs[0].TextStyle = focusedStyle
-	if len(dc.Title) > 0 && dc.Title[0] != "" {
-		m.inputs[0].SetValue(dc.Title[0])
+	for i, field := range formFields {
+		var value string
+		values, _ := dc.Get(field.name)
+		if field.multi() && len(values) > 0 {
+			value = strings.Join(values, ", ")
+		} else if len(values) > 0 {
+			value = values[0]
+		}
+		m.initial[i] = value
+
+		if field.multiline {
+			m.areas[i] = textarea.New()
+			m.areas[i].Placeholder = field.placeholder
+			m.areas[i].ShowLineNumbers = false
+			m.areas[i].CharLimit = 0 // no limit: abstracts can span paragraphs
+			m.areas[i].SetWidth(areaWidth)
+			m.areas[i].SetHeight(areaHeight)
+			m.areas[i].SetValue(value)
+			m.areas[i].Blur()
+			continue
+		}
+
+		m.inputs[i] = textinput.New()
+		m.inputs[i].Placeholder = field.placeholder
+		m.inputs[i].PlaceholderStyle = placeholderStyle
+		m.inputs[i].PromptStyle = blurryStyle
+		m.inputs[i].SetValue(value)
+		if m.suggests(i) {
+			m.inputs[i].ShowSuggestions = true
+			// Tab moves between fields, so suggestions are accepted by acceptSuggestion
+			m.inputs[i].KeyMap.AcceptSuggestion.SetEnabled(false)
+		}
 	}
 
-	// Creator input
-	m.inputs[1] = textinput.New()
-	m.inputs[1].Placeholder = "e.g., João Silva, Maria Santos"
-	m.inputs[1].PlaceholderStyle = placeholderStyle
-	m.inputs[1].PromptStyle = blurryStyle
-	if len(dc.Creator) > 0 {
-		m.inputs[1].SetValue(strings.Join(dc.Creator, ", "))
+	m.focusField(0)
+
+	return m
+}
+
+// moveFocus moves the focus by delta stops, cycling through every input and
+// the submit button (stop len(m.inputs)) in both directions
+func (m *model) moveFocus(delta int) tea.Cmd {
+	stops := len(m.inputs) + 1
+	m.focused = ((m.focused+delta)%stops + stops) % stops
+
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		if i == m.focused {
+			cmds[i] = m.focusField(i)
+			continue
+		}
+		m.blurField(i)
 	}
+	m.scrollToFocused()
 
-	// Keywords input
-	m.inputs[2] = textinput.New()
-	m.inputs[2].Placeholder = "e.g., Go, Backend, Microservices, PHP"
-	m.inputs[2].PlaceholderStyle = placeholderStyle
-	m.inputs[2].PromptStyle = blurryStyle
-	if len(dc.Keywords) > 0 {
-		m.inputs[2].SetValue(strings.Join(dc.Keywords, ", "))
+	return tea.Batch(cmds...)
+}
+
+// focusField focuses the input of field i
+func (m *model) focusField(i int) tea.Cmd {
+	if formFields[i].multiline {
+		return m.areas[i].Focus()
 	}
+	m.inputs[i].PromptStyle = focusedStyle
+	m.inputs[i].TextStyle = focusedStyle
+	return m.inputs[i].Focus()
+}
 
-	// Description input
-	m.inputs[3] = textinput.New()
-	m.inputs[3].Placeholder = "e.g., Experienced backend developer with 6+ years in technology"
-	m.inputs[3].PlaceholderStyle = placeholderStyle
-	m.inputs[3].PromptStyle = blurryStyle
-	m.inputs[3].CharLimit = 200
-	if len(dc.Description) > 0 && dc.Description[0] != "" {
-		m.inputs[3].SetValue(dc.Description[0])
+// blurField removes the focus from the input of field i
+func (m *model) blurField(i int) {
+	if formFields[i].multiline {
+		m.areas[i].Blur()
+		return
 	}
+	m.inputs[i].Blur()
+	m.inputs[i].PromptStyle = blurryStyle
+	m.inputs[i].TextStyle = blurryStyle
+}
 
-	return m
+// fieldValue returns the text typed into field i
+func (m model) fieldValue(i int) string {
+	if formFields[i].multiline {
+		return m.areas[i].Value()
+	}
+	return m.inputs[i].Value()
+}
+
+// fieldView renders the input of field i
+func (m model) fieldView(i int) string {
+	if formFields[i].multiline {
+		return m.areas[i].View()
+	}
+	return m.inputs[i].View()
+}
+
+// movesWithinArea reports whether an up/down key moves the cursor inside the
+// focused multiline input rather than to the neighbouring field
+func (m model) movesWithinArea(key string) bool {
+	if m.focused >= len(m.inputs) || !formFields[m.focused].multiline {
+		return false
+	}
+	area := m.areas[m.focused]
+	switch key {
+	case "up":
+		return area.Line() > 0
+	case "down":
+		return area.Line() < area.LineCount()-1
+	}
+	return false
 }
 
 func (m model) Init() tea.Cmd {
@@ -81,7 +208,16 @@ func (m model) Init() tea.Cmd {
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.scrollToFocused()
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.confirming {
+			return m.updateConfirm(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			m.cancelled = true
@@ -89,129 +225,297 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "tab", "shift+tab", "up", "down":
 			s := msg.String()
+			if m.movesWithinArea(s) {
+				break
+			}
 
-			// Cycle indexes
 			if s == "up" || s == "shift+tab" {
-				m.focused--
-			} else {
-				m.focused++
+				return m, m.moveFocus(-1)
 			}
+			return m, m.moveFocus(1)
 
-			if m.focused > len(m.inputs) {
-				m.focused = 0
-			} else if m.focused < 0 {
-				m.focused = len(m.inputs)
+		case "ctrl+u":
+			if m.focused < len(m.inputs) {
+				m.clearFocused()
+				m.refreshSuggestions()
+				return m, nil
 			}
 
-			cmds := make([]tea.Cmd, len(m.inputs))
-			for i := 0; i <= len(m.inputs)-1; i++ {
-				if i == m.focused {
-					cmds[i] = m.inputs[i].Focus()
-					m.inputs[i].PromptStyle = focusedStyle
-					m.inputs[i].TextStyle = focusedStyle
-					continue
-				}
-				m.inputs[i].Blur()
-				m.inputs[i].PromptStyle = blurryStyle
-				m.inputs[i].TextStyle = blurryStyle
+		case "right":
+			if m.acceptSuggestion() {
+				return m, nil
 			}
 
-			return m, tea.Batch(cmds...)
-
 		case "enter":
 			if m.focused == len(m.inputs) {
-				m.done = true
-				// Update the Dublin Core object with user input before quitting
-				m.updateDublinCoreFromInputs()
-				return m, tea.Quit
+				// Review the changes on a copy before touching the original
+				m.pending = m.dc.Clone()
+				m.updateDublinCoreFromInputs(m.pending)
+				m.confirming = true
+				return m, nil
 			}
 		}
 	}
 
 	cmd := m.updateInputs(msg)
+	m.refreshSuggestions()
 	return m, cmd
 }
 
+// suggests reports whether field i completes keywords from the vocabulary
+func (m model) suggests(i int) bool {
+	return i < len(m.inputs) && formFields[i].name == "keywords" && len(m.vocabulary) > 0
+}
+
+// refreshSuggestions offers the vocabulary terms that complete the keyword
+// being typed in the focused input, leaving out the keywords already entered
+func (m *model) refreshSuggestions() {
+	if !m.suggests(m.focused) {
+		return
+	}
+	input := &m.inputs[m.focused]
+
+	value := input.Value()
+	typed := strings.TrimLeft(value[strings.LastIndex(value, ",")+1:], " ")
+	if typed == "" {
+		input.SetSuggestions(nil)
+		return
+	}
+	head := value[:len(value)-len(typed)]
+
+	var entered []string
+	for _, keyword := range strings.Split(head, ",") {
+		entered = append(entered, strings.TrimSpace(keyword))
+	}
+
+	// The input matches suggestions against its whole value, so each one
+	// repeats the keywords before the one being typed
+	var suggestions []string
+	for _, term := range m.vocabulary {
+		if !containsFold(entered, term) {
+			suggestions = append(suggestions, head+term)
+		}
+	}
+	input.SetSuggestions(suggestions)
+}
+
+// acceptSuggestion completes the keyword being typed with the selected
+// suggestion when the cursor is at the end of the input
+func (m *model) acceptSuggestion() bool {
+	if !m.suggests(m.focused) {
+		return false
+	}
+	input := &m.inputs[m.focused]
+
+	suggestion := input.CurrentSuggestion()
+	if suggestion == "" || input.Position() < len([]rune(input.Value())) {
+		return false
+	}
+	// The vocabulary's spelling wins over the typed case, and the separator readies the next keyword
+	input.SetValue(suggestion + ", ")
+	input.CursorEnd()
+	m.refreshSuggestions()
+	return true
+}
+
+// containsFold reports whether values holds value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// clearFocused empties the focused input; saving then clears the field
+func (m *model) clearFocused() {
+	if formFields[m.focused].multiline {
+		m.areas[m.focused].Reset()
+		return
+	}
+	m.inputs[m.focused].SetValue("")
+}
+
+// updateConfirm handles the keys of the confirmation screen
+func (m model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.cancelled = true
+		return m, tea.Quit
+	case "y", "Y", "enter":
+		m.dc = m.pending
+		m.done = true
+		return m, tea.Quit
+	case "n", "N", "esc":
+		m.confirming = false
+		m.pending = nil
+	}
+	return m, nil
+}
+
 func (m *model) updateInputs(msg tea.Msg) tea.Cmd {
 	cmds := make([]tea.Cmd, len(m.inputs))
 	for i := range m.inputs {
+		if formFields[i].multiline {
+			m.areas[i], cmds[i] = m.areas[i].Update(msg)
+			continue
+		}
 		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
 	}
 	return tea.Batch(cmds...)
 }
 
-func (m *model) updateDublinCoreFromInputs() {
-	// Title
-	titleInput := strings.TrimSpace(m.inputs[0].Value())
-	if titleInput != "" && titleInput != m.inputs[0].Placeholder {
-		m.dc.SetTitle(titleInput)
+// visibleFields returns how many inputs fit on screen
+func (m model) visibleFields() int {
+	if m.height == 0 {
+		return len(m.inputs)
 	}
+	visible := (m.height - headerLines - footerLines - (areaHeight - 1)) / fieldLines
+	if visible < 1 {
+		visible = 1
+	}
+	if visible > len(m.inputs) {
+		visible = len(m.inputs)
+	}
+	return visible
+}
 
-	// Creator
-	creatorsInput := strings.TrimSpace(m.inputs[1].Value())
-	if creatorsInput != "" && creatorsInput != m.inputs[1].Placeholder {
-		newCreators := []string{}
-		for _, creator := range strings.Split(creatorsInput, ",") {
-			if trimmed := strings.TrimSpace(creator); trimmed != "" {
-				newCreators = append(newCreators, trimmed)
-			}
-		}
-		m.dc.Creator = newCreators
+// scrollToFocused moves the visible window so the focused input is on screen
+func (m *model) scrollToFocused() {
+	visible := m.visibleFields()
+	focused := m.focused
+	if focused >= len(m.inputs) {
+		// The submit button sits below the last input
+		focused = len(m.inputs) - 1
+	}
+
+	if focused < m.offset {
+		m.offset = focused
+	} else if focused >= m.offset+visible {
+		m.offset = focused - visible + 1
 	}
+	if m.offset > len(m.inputs)-visible {
+		m.offset = len(m.inputs) - visible
+	}
+}
+
+// updateDublinCoreFromInputs copies the edited input values into dc. Fields
+// left as they started are untouched; a field emptied by the user is cleared.
+func (m *model) updateDublinCoreFromInputs(dc *dublincore.DublinCore) {
+	for i, field := range formFields {
+		input := strings.TrimSpace(m.fieldValue(i))
+		if input == strings.TrimSpace(m.initial[i]) {
+			continue
+		}
 
-	// Keywords
-	keywordsInput := strings.TrimSpace(m.inputs[2].Value())
-	if keywordsInput != "" && keywordsInput != m.inputs[2].Placeholder {
-		newKeywords := []string{}
-		for _, keyword := range strings.Split(keywordsInput, ",") {
-			if trimmed := strings.TrimSpace(keyword); trimmed != "" {
-				newKeywords = append(newKeywords, trimmed)
+		if field.multi() {
+			values := []string{}
+			for _, value := range strings.Split(input, ",") {
+				if trimmed := strings.TrimSpace(value); trimmed != "" {
+					values = append(values, trimmed)
+				}
 			}
+			dc.Set(field.name, values)
+			continue
 		}
-		m.dc.Keywords = newKeywords
-	}
 
-	// Description
-	descriptionInput := strings.TrimSpace(m.inputs[3].Value())
-	if descriptionInput != "" && descriptionInput != m.inputs[3].Placeholder {
-		m.dc.SetDescription(descriptionInput)
+		// Only the first value is editable; repeated entries are kept as they are
+		values, _ := dc.Get(field.name)
+		var rest []string
+		if len(values) > 1 {
+			rest = values[1:]
+		}
+		if input == "" {
+			dc.Set(field.name, rest)
+			continue
+		}
+		dc.Set(field.name, append([]string{input}, rest...))
 	}
 
 	// Always set category to "curriculo"
-	m.dc.SetCategory()
+	dc.SetCategory()
 }
 
-func (m model) View() string {
+// extraValues returns how many repeated values of a single-value field aren't shown in its input
+func (m model) extraValues(i int) int {
+	if formFields[i].multi() {
+		return 0
+	}
+	values, _ := m.dc.Get(formFields[i].name)
+	if len(values) < 2 {
+		return 0
+	}
+	return len(values) - 1
+}
+
+// confirmView summarizes the pending changes and asks whether to save them
+func (m model) confirmView() string {
 	var b strings.Builder
 
-	b.WriteString(titleStyle.Render("📄 Dublin Core Metadata Editor\n\n"))
+	b.WriteString(titleStyle.Render("📄 Review Changes\n\n"))
 
-	// Title field
-	b.WriteString(fieldLabelStyle.Render("DC: Title") + "\n")
-	b.WriteString(m.inputs[0].View())
-	b.WriteString("\n\n")
+	diffs := dublincore.Diff(m.dc, m.pending)
+	if len(diffs) == 0 {
+		b.WriteString(helpStyle.Render("No changes to save.") + "\n\n")
+	}
+	for _, d := range diffs {
+		b.WriteString(fieldLabelStyle.Render(d.Field) + "\n")
+		b.WriteString(blurryStyle.Render("  - "+valueOrNone(d.Before)) + "\n")
+		b.WriteString(currentValueStyle.Render("  + "+valueOrNone(d.After)) + "\n\n")
+	}
 
-	// Creator field
-	b.WriteString(fieldLabelStyle.Render("DC: Creator (comma-separated)") + "\n")
-	b.WriteString(m.inputs[1].View())
-	b.WriteString("\n\n")
+	b.WriteString(helpStyle.Render("y/Enter: Save • n/Esc: Back to editing • Ctrl+C: Cancel"))
 
-	// Keywords field
-	b.WriteString(fieldLabelStyle.Render("CP: Keywords (comma-separated)") + "\n")
-	b.WriteString(m.inputs[2].View())
-	b.WriteString("\n\n")
+	return b.String()
+}
 
-	// Description field
-	b.WriteString(fieldLabelStyle.Render("CP: Description") + "\n")
-	b.WriteString(m.inputs[3].View())
-	b.WriteString("\n\n")
+// valueOrNone joins values for display, with a placeholder for empty fields
+func valueOrNone(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+	return strings.Join(values, ", ")
+}
+
+func (m model) View() string {
+	if m.confirming {
+		return m.confirmView()
+	}
+
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("📄 Dublin Core Metadata Editor\n\n"))
+
+	visible := m.visibleFields()
+	if m.offset > 0 {
+		b.WriteString(helpStyle.Render("↑ more fields") + "\n")
+	}
+	for i := m.offset; i < m.offset+visible; i++ {
+		b.WriteString(fieldLabelStyle.Render(formFields[i].label))
+		if extra := m.extraValues(i); extra > 0 {
+			b.WriteString(helpStyle.Render(fmt.Sprintf(" (+%d more kept)", extra)))
+		}
+		if i == m.focused && m.suggests(i) {
+			if matches := len(m.inputs[i].MatchedSuggestions()); matches > 0 {
+				current := m.inputs[i].CurrentSuggestionIndex() + 1
+				b.WriteString(helpStyle.Render(fmt.Sprintf(" (→: accept suggestion %d of %d • Ctrl+N/Ctrl+P: next/previous)", current, matches)))
+			}
+		}
+		b.WriteString("\n")
+		b.WriteString(m.fieldView(i))
+		b.WriteString("\n\n")
+	}
+	if m.offset+visible < len(m.inputs) {
+		b.WriteString(helpStyle.Render("↓ more fields") + "\n\n")
+	}
 
 	// Category field (read-only)
 	b.WriteString(fieldLabelStyle.Render("CP: Category") + "\n")
 	b.WriteString("curriculo (fixed value)\n\n")
 
 	// Navigation help
-	b.WriteString(helpStyle.Render("↑/↓: Navigate • Tab/Shift+Tab: Next/Previous • Enter: Submit • Esc: Cancel"))
+	b.WriteString(helpStyle.Render("↑/↓: Navigate • Tab/Shift+Tab: Next/Previous • Ctrl+U: Clear field • Enter: Submit • Esc: Cancel"))
 	b.WriteString("\n\n")
 
 	// Submit button
@@ -224,9 +528,10 @@ func (m model) View() string {
 	return b.String()
 }
 
-// RunEditor starts the BubbleTea TUI and returns updated metadata
-func RunEditor(dc *dublincore.DublinCore) (*dublincore.DublinCore, bool, error) {
-	p := tea.NewProgram(initialModel(dc))
+// RunEditor starts the BubbleTea TUI and returns updated metadata. Terms of
+// vocabulary are suggested while typing keywords; nil disables suggestions.
+func RunEditor(dc *dublincore.DublinCore, vocabulary []string) (*dublincore.DublinCore, bool, error) {
+	p := tea.NewProgram(initialModel(dc, vocabulary))
 
 	finalModel, err := p.Run()
 	if err != nil {
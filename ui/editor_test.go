@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/eduardo-moro/metadata-editor/dublincore"
+)
+
+var (
+	keyTab      = tea.KeyMsg{Type: tea.KeyTab}
+	keyShiftTab = tea.KeyMsg{Type: tea.KeyShiftTab}
+	keyUp       = tea.KeyMsg{Type: tea.KeyUp}
+	keyDown     = tea.KeyMsg{Type: tea.KeyDown}
+	keyEnter    = tea.KeyMsg{Type: tea.KeyEnter}
+	keyEsc      = tea.KeyMsg{Type: tea.KeyEsc}
+	keyRight    = tea.KeyMsg{Type: tea.KeyRight}
+	keyClear    = tea.KeyMsg{Type: tea.KeyCtrlU}
+)
+
+// typed returns the key message for typing text
+func typed(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+// send passes keys through Update in order and returns the resulting model
+// and the command of the last key
+func send(t *testing.T, m model, keys ...tea.KeyMsg) (model, tea.Cmd) {
+	t.Helper()
+
+	var cmd tea.Cmd
+	for _, key := range keys {
+		var updated tea.Model
+		updated, cmd = m.Update(key)
+		m = updated.(model)
+	}
+	return m, cmd
+}
+
+// isQuit reports whether cmd ends the program
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+// fieldIndex returns the position of the named field in the form
+func fieldIndex(t *testing.T, name string) int {
+	t.Helper()
+
+	for i, field := range formFields {
+		if field.name == name {
+			return i
+		}
+	}
+	t.Fatalf("no %s field in the form", name)
+	return -1
+}
+
+func sampleMetadata() *dublincore.DublinCore {
+	return &dublincore.DublinCore{
+		Title:    []string{"Analista"},
+		Creator:  []string{"Ana", "Bruno"},
+		Keywords: []string{"Go"},
+		Rights:   []string{"CC-BY-4.0"},
+	}
+}
+
+func TestFocusCycles(t *testing.T) {
+	submit := len(formFields)
+
+	tests := []struct {
+		name string
+		keys []tea.KeyMsg
+		want int
+	}{
+		{"tab", []tea.KeyMsg{keyTab}, 1},
+		{"down", []tea.KeyMsg{keyDown, keyDown}, 2},
+		{"shift+tab from the first field", []tea.KeyMsg{keyShiftTab}, submit},
+		{"up from the first field", []tea.KeyMsg{keyUp}, submit},
+		{"tab from submit", []tea.KeyMsg{keyShiftTab, keyTab}, 0},
+		{"down from submit", []tea.KeyMsg{keyUp, keyDown}, 0},
+		{"back and forth", []tea.KeyMsg{keyTab, keyTab, keyShiftTab}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := send(t, initialModel(sampleMetadata(), nil), tt.keys...)
+			if m.focused != tt.want {
+				t.Errorf("focused = %d, want %d", m.focused, tt.want)
+			}
+		})
+	}
+
+	t.Run("full cycle", func(t *testing.T) {
+		m := initialModel(sampleMetadata(), nil)
+		seen := map[int]bool{}
+		for range submit + 1 {
+			seen[m.focused] = true
+			m, _ = send(t, m, keyTab)
+		}
+		if m.focused != 0 || len(seen) != submit+1 {
+			t.Errorf("a full cycle visited %d stops and ended on %d, want %d stops ending on 0", len(seen), m.focused, submit+1)
+		}
+	})
+}
+
+func TestEditAndSave(t *testing.T) {
+	dc := sampleMetadata()
+	m := initialModel(dc, nil)
+
+	// Replace the title, append a creator and clear the rights
+	m, _ = send(t, m, keyClear, typed("Analista Go"), keyTab, typed(", Carla"))
+	for m.focused != fieldIndex(t, "rights") {
+		m, _ = send(t, m, keyTab)
+	}
+	m, _ = send(t, m, keyClear, keyTab)
+	if m.focused != len(formFields) {
+		t.Fatalf("focused = %d after the last field, want the submit button", m.focused)
+	}
+
+	m, cmd := send(t, m, keyEnter)
+	if !m.confirming || isQuit(cmd) {
+		t.Fatal("Enter on submit didn't open the confirmation")
+	}
+	if !slices.Equal(dc.Title, []string{"Analista"}) {
+		t.Errorf("the original was changed before confirming: Title = %q", dc.Title)
+	}
+
+	m, cmd = send(t, m, typed("y"))
+	if !m.done || m.cancelled || !isQuit(cmd) {
+		t.Fatalf("done = %v, cancelled = %v, quit = %v after confirming, want done and quit", m.done, m.cancelled, isQuit(cmd))
+	}
+
+	for _, tt := range []struct {
+		field string
+		want  []string
+	}{
+		{"title", []string{"Analista Go"}},
+		{"creator", []string{"Ana", "Bruno", "Carla"}},
+		{"keywords", []string{"Go"}},
+		{"rights", nil},
+		{"category", []string{"curriculo"}},
+	} {
+		got, _ := m.dc.Get(tt.field)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmationBack(t *testing.T) {
+	m, _ := send(t, initialModel(sampleMetadata(), nil), typed("!"), keyShiftTab, keyEnter)
+	if !m.confirming {
+		t.Fatal("Enter on submit didn't open the confirmation")
+	}
+
+	m, cmd := send(t, m, typed("n"))
+	if m.confirming || m.pending != nil || m.done || isQuit(cmd) {
+		t.Fatalf("n didn't return to editing: confirming = %v, done = %v", m.confirming, m.done)
+	}
+	if got := m.inputs[0].Value(); got != "Analista!" {
+		t.Errorf("title input = %q after going back, want the edit kept", got)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	for _, key := range []tea.KeyMsg{keyEsc, {Type: tea.KeyCtrlC}} {
+		t.Run(key.String(), func(t *testing.T) {
+			dc := sampleMetadata()
+			m, cmd := send(t, initialModel(dc, nil), typed("!"), key)
+			if !m.cancelled || m.done || !isQuit(cmd) {
+				t.Errorf("cancelled = %v, done = %v, quit = %v, want cancelled and quit", m.cancelled, m.done, isQuit(cmd))
+			}
+			if !slices.Equal(dc.Title, []string{"Analista"}) {
+				t.Errorf("cancelling changed Title to %q", dc.Title)
+			}
+		})
+	}
+}
+
+func TestDescriptionLines(t *testing.T) {
+	description := fieldIndex(t, "description")
+	m := initialModel(sampleMetadata(), nil)
+	for m.focused != description {
+		m, _ = send(t, m, keyTab)
+	}
+
+	m, _ = send(t, m, typed("first"), keyEnter, typed("second"), keyUp)
+	if m.focused != description {
+		t.Fatalf("up on the second line left the field, focused = %d", m.focused)
+	}
+	if got := m.areas[description].Value(); got != "first\nsecond" {
+		t.Errorf("description = %q, want two lines", got)
+	}
+
+	m, _ = send(t, m, keyUp)
+	if m.focused != description-1 {
+		t.Errorf("up on the first line focused %d, want the previous field", m.focused)
+	}
+}
+
+func TestKeywordSuggestions(t *testing.T) {
+	keywords := fieldIndex(t, "keywords")
+	m := initialModel(&dublincore.DublinCore{}, []string{"Go", "Kubernetes", "Kotlin"})
+	for m.focused != keywords {
+		m, _ = send(t, m, keyTab)
+	}
+
+	m, _ = send(t, m, typed("go, ku"), keyRight)
+	if got := m.inputs[keywords].Value(); got != "go, Kubernetes, " {
+		t.Errorf("keywords = %q after accepting, want the suggestion completed", got)
+	}
+
+	// Terms already entered aren't offered again
+	m, _ = send(t, m, typed("g"))
+	if got := m.inputs[keywords].MatchedSuggestions(); len(got) != 0 {
+		t.Errorf("suggested %q, want none for an entered keyword", got)
+	}
+}